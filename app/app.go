@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"github.com/Bios-Marcel/cordless/commands/commandimpls"
 	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/mentions"
 	"github.com/Bios-Marcel/cordless/readstate"
 	"github.com/Bios-Marcel/cordless/shortcuts"
 	"github.com/Bios-Marcel/cordless/ui"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
 	"github.com/Bios-Marcel/cordless/version"
 	"github.com/Bios-Marcel/discordgo"
 	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
 	"log"
 	"os"
 )
@@ -36,6 +40,11 @@ func Run() {
 	}
 
 	app := tview.NewApplication()
+	//Role colours are mapped to the colours that the terminal can display.
+	app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		tviewutil.SetTerminalColors(screen.Colors())
+		return false
+	})
 	loginScreen := ui.NewLogin(app, configDir)
 	app.SetRoot(loginScreen, true)
 	runNext := make(chan bool, 1)
@@ -63,6 +72,11 @@ func Run() {
 			panic(shortcutsLoadError)
 		}
 
+		mentionsLoadError := mentions.Load()
+		if mentionsLoadError != nil {
+			log.Printf("Error loading mentions inbox (%s).\n", mentionsLoadError.Error())
+		}
+
 		discord := attemptLogin(loginScreen, defaultLoginMessage, app, configuration)
 
 		config.GetConfig().Token = discord.Token
@@ -77,6 +91,19 @@ func Run() {
 		discord.AddHandlerOnce(func(s *discordgo.Session, event *discordgo.Ready) {
 			readyChan <- event
 		})
+		//The end times of mutes are missing in the parsed event.
+		discord.AddHandler(func(s *discordgo.Session, event *discordgo.Event) {
+			if event.Type != "READY" {
+				return
+			}
+
+			muteEndTimes, parseError := discordutil.GetReadyMuteEndTimes(event.RawData)
+			if parseError != nil {
+				log.Printf("Error reading mute end times (%s).\n", parseError.Error())
+				return
+			}
+			readstate.UpdateMuteEndTimes(muteEndTimes)
+		})
 
 		discordError := discord.Open()
 		if discordError != nil {
@@ -151,6 +178,9 @@ func Run() {
 			window.RegisterCommand(serverJoinCmd)
 			window.RegisterCommand(serverLeaveCmd)
 			window.RegisterCommand(commandimpls.NewServerCommand(serverJoinCmd, serverLeaveCmd))
+			window.RegisterCommand(commandimpls.NewMuteCommand(window, discord))
+			window.RegisterCommand(commandimpls.NewUnmuteCommand(window, discord))
+			window.RegisterCommand(commandimpls.NewMarkReadCommand(window, discord))
 		})
 	}()
 
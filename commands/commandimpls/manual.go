@@ -113,12 +113,46 @@ const chatViewDocumentation = `[::b]TOPIC
 	| Reply with mention          | r          |
 	| Quote message               | q          |
 	| Hide / show spoiler content | s          |
+	| Load older messages         | o          |
+	| Jump to first unread        | u          |
+	| Find text in messages       | /          |
+	| Jump to next match          | n          |
+	| Jump to previous match      | Shift+N    |
+	| Show links                  | Shift+L    |
+	| Show edit history           | h          |
 	| Selection up                | ArrowUp    |
 	| Selection down              | ArrowDown  |
 	| Selection to top            | Home       |
 	| Selection to bottom         | End        |
 	--------------------------------------------
 
+	When opening a channel, the first message that you haven't read yet is
+	marked with a "new messages" line. The line disappears once the channel
+	has been read on a different device or when you reopen the channel.
+
+	Moving the selection past the first message or scrolling past the top
+	with your mousewheel loads the previous messages as well. Once the
+	beginning of the channel has been reached, nothing will be loaded anymore.
+
+	Hitting / lets you type a search text. All occurrences of the text in the
+	loaded messages are highlighted while you type and the newest match gets
+	selected. Enter confirms the search text, allowing you to use n and
+	Shift+N to cycle through the matches. Escape leaves the search.
+
+	Hitting Shift+L shows a numbered list of all links and attachments in
+	the selected message or, if no message is selected, in all visible
+	messages. Number keys select a link, Enter opens it, c copies it and d
+	asks for a directory to download it to.
+
+	Edited messages are marked with "(edited)". Hitting h on an edited
+	message shows all versions of it that cordless has seen since it was
+	started. Each version is compared to the previous one, removed words
+	are struck through and added words are highlighted.
+
+	While others are typing in the current channel, a line below the
+	messages tells who is typing. Whether others see that you are typing
+	can be changed via the SendTypingNotifications setting.
+
 	Keep in mind, that those shortcuts might differ from your settings, as
 	those are just the defaults.`
 
@@ -210,13 +244,120 @@ const configurationDocumentation = `[::b]TOPIC
 		Type:    int
 		Default: NoTime (2)
 		
+	[::b]TimeFormat
+		Determines how message timestamps are rendered in the chatview by
+		using a go time layout, for example [::b]15:04 Mon[::-]. If set, this
+		setting takes precedence over [::b]Times[::-].
+
+		Type:    string
+		Default: EMPTY
+
+	[::b]RelativeTimes
+		Determines whether the times of messages from the last seven days
+		are shown relative to now, for example "5m ago". Those times are
+		updated every 30 seconds.
+
+		Type:    boolean
+		Default: false
+
+	[::b]Timezone
+		Determines the timezone that all times and dates are shown in. The
+		value is the name of an IANA timezone, for example
+		[::b]Europe/Berlin[::-]. If empty, the timezone of your system is used.
+
+		Type:    string
+		Default: EMPTY
+
+	[::b]DateDelimiterFormat
+		Determines how the date shown between messages of different days is
+		rendered by using a go time layout, for example
+		[::b]Monday, 2. January 2006[::-].
+
+		Type:    string
+		Default: 2006-01-02
+
+	[::b]DateLocale
+		Determines the language of weekdays and months in dates and times.
+		Available languages are de, es, fr, it, nl and pt. If empty, english
+		is used.
+
+		Type:    string
+		Default: EMPTY
+
 	[::b]UseRandomUserColors
 		Determines whether all usernames will have the same color or a color
-		randomly chosen from a pool of predefined colors.
+		chosen from a pool of predefined colors. Each user always gets the
+		same color, as it is derived from the users ID.
 		
 		Type:    boolean
 		Default: false
 		
+	[::b]AvoidLowContrastUserColors
+		Determines whether colors of the pool used by
+		[::b]UseRandomUserColors[::-] are skipped if they are hard to read on
+		the background of the current theme.
+		
+		Type:    boolean
+		Default: false
+		
+	[::b]UseRoleColors
+		Determines whether users in the chatview and the user list are
+		colored with the color of their highest colored role, just like in
+		the official client. If the terminal can't display the exact color,
+		the closest available color is used. Users without a colored role
+		are colored according to [::b]UseRandomUserColors[::-].
+		
+		Type:    boolean
+		Default: false
+		
+	[::b]MessageTemplate
+		Determines the layout of each message in the chatview. The
+		following placeholders are replaced with the respective values:
+
+		--------------------------------------------------------------
+		|   Placeholder   |                  Value                   |
+		| --------------- | ---------------------------------------- |
+		| {time}          | Time of the message, see [::b]Times[::-]           |
+		| {author}        | Coloured nickname or username            |
+		| {nick}          | Nickname or username without colour      |
+		| {username}      | Username, even if a nickname is set      |
+		| {discriminator} | The four digits following the username   |
+		| {rolecolor}     | Colours the following text in the colour |
+		|                 | of the authors highest coloured role     |
+		| {channel}       | Name of the channel of the message       |
+		| {content}       | The message itself                       |
+		--------------------------------------------------------------
+
+		For example, IRC style messages can be achieved with the template
+		[::b]{time} <{author}> {content}[::-].
+
+		Type:    string
+		Default: {time} {author} {content}
+
+	[::b]AuthorColumnWidth
+		Determines the width of the column that author names are
+		right-aligned in. Names that are too long get shortened. A width of
+		0 disables the column.
+
+		Type:    int
+		Default: 0
+
+	[::b]CompactMessages
+		Determines whether the time is hidden for messages that directly
+		follow a message of the same author.
+
+		Type:    boolean
+		Default: false
+
+	[::b]MessageGroupingWindow
+		Determines the amount of seconds in which consecutive messages of
+		the same author are grouped together. Only the first message of a
+		group shows the time and the author, all following messages are
+		indented instead. A value of 0 disables grouping.
+
+		Type:    int
+		Default: 0
+
 	[::b]FocusChannelAfterGuildSelection
 		Determines whether the focus automatically jumps to the channeltree
 		after selecting a guild from the guildlist.
@@ -248,6 +389,14 @@ const configurationDocumentation = `[::b]TOPIC
 		Type:    boolean
 		Default: true
 		
+	[::b]GroupOfflineMembers
+		Determines whether offline members of a guild are moved into a
+		collapsed "Offline" group at the end of the user list. Selecting
+		the group expands or collapses it.
+		
+		Type:    boolean
+		Default: false
+		
 	[::b]UseFixedLayout
 		Determines whether the guild list and the channel tree use a fixed
 		width or take horizontal space relative to the window size.
@@ -269,6 +418,14 @@ const configurationDocumentation = `[::b]TOPIC
 		Type:    int
 		Default: 12
 		
+	[::b]SplitViewSideBySide
+		Determines whether the two panes of a split chat are shown next to
+		each other or on top of each other. The setting can also be toggled
+		via [::b]Alt+Shift+V[::-].
+		
+		Type:    boolean
+		Default: true
+		
 	[::b]OnTypeInListBehaviour
 		Determines whether typing in a list or tree-list will trigger a text
 		search, do nothing or focus the message-input.
@@ -310,6 +467,21 @@ const configurationDocumentation = `[::b]TOPIC
 		Determines which port the link-shortener uses in your system. This
 		setting only matters if [::b]ShortenLinks[::-] is set to [::b]true[::-]
 		
+	[::b]LinkOpenCommand
+		Determines the command that is used for opening links. The
+		placeholder [::b]{url}[::-] is replaced with the link. If the command
+		doesn't contain the placeholder, the link is appended to the command.
+		
+		Type:    string
+		Default: xdg-open {url} (open {url} on MacOS)
+		
+	[::b]DownloadDirectory
+		Determines the directory that is suggested when downloading links or
+		attachments.
+		
+		Type:    string
+		Default: ~/Downloads
+		
 	[::b]DesktopNotifications
 		Determines whether cordless will try to notify the host systems using
 		the systems notification system. This setting might not work on all
@@ -318,6 +490,13 @@ const configurationDocumentation = `[::b]TOPIC
 		Type:    boolean
 		Default: true
 		
+	[::b]SendTypingNotifications
+		Determines whether other users will see that you are typing a
+		message. Typing in the command input is never shared.
+		
+		Type:    boolean
+		Default: true
+		
 	[::b]ShowPlaceholderForBlockedMessages
 		Determines whether blocked messages are hidden or a placeholder is
 		shown instead, so that you know that someone sent a message. This
@@ -326,6 +505,25 @@ const configurationDocumentation = `[::b]TOPIC
 		
 		Type:    boolean
 		Default: true
+		
+	[::b]DeletedMessageBehaviour
+		Determines what happens to messages that have been deleted. By
+		default they are removed from the chatview. Alternatively they can
+		be kept until the channel is reloaded, marked as "deleted" and
+		either struck through or dimmed.
+		
+		This settings has three different possible values:
+		
+		----------------------------------------
+		|             Name             | Value |
+		| ---------------------------- | ----- |
+		| RemoveDeletedMessages        | 0     |
+		| StrikeThroughDeletedMessages | 1     |
+		| DimDeletedMessages           | 2     |
+		----------------------------------------
+		
+		Type:    int
+		Default: RemoveDeletedMessages (0)
 
 	[::b]Accounts
 		This settings holds an array of so called accounts, also referred to
@@ -379,22 +577,87 @@ const navigationDocumentation = `[::b]TOPIC
 
 	By default the navigation is done via the following shortcuts:
 
-	--------------------------------------------------------------------
-	|          Action         | Shortcut |            Scope            |
-	| ----------------------- | -------- | ----------------------------|
-	| Close application       | Ctrl-C   | Everywhere                  |
-	| Focus user container    | Alt+U    | Guild channel / group chat  |
-	| Focus private chat page | Alt+P    | Everywhere                  |
-	| Focus guild container   | Alt+S    | Everywhere                  |
-	| Focus channel container | Alt+C    | Everywhere                  |
-	| Focus message input     | Alt+M    | Everywhere                  |
-	| Focus message container | Alt+T    | Everywhere                  |
-	| Toggle command view     | Alt+Dot  | Everywhere                  |
-	| Focus command output    | Ctrl+O   | Everywhere                  |
-	| Focus command input     | Ctrl+I   | Everywhere                  |
-	| Edit last message       | ArrowUp  | In empty message input      |
-	| Leave message edit mode | Esc      | When editing message        |
-	--------------------------------------------------------------------
+	----------------------------------------------------------------------
+	|          Action         |   Shortcut  |           Scope            |
+	| ----------------------- | ----------- | -------------------------- |
+	| Close application       | Ctrl-C      | Everywhere                 |
+	| Focus user container    | Alt+U       | Guild channel / group chat |
+	| Focus private chat page | Alt+P       | Everywhere                 |
+	| Focus guild container   | Alt+S       | Everywhere                 |
+	| Focus channel container | Alt+C       | Everywhere                 |
+	| Focus message input     | Alt+M       | Everywhere                 |
+	| Focus message container | Alt+T       | Everywhere                 |
+	| Toggle command view     | Alt+Dot     | Everywhere                 |
+	| Focus command output    | Ctrl+O      | Everywhere                 |
+	| Focus command input     | Ctrl+I      | Everywhere                 |
+	| Edit last message       | ArrowUp     | In empty message input     |
+	| Leave message edit mode | Esc         | When editing message       |
+	| Open new tab            | Alt+Shift+T | Everywhere                 |
+	| Close current tab       | Alt+W       | Everywhere                 |
+	| Switch to next tab      | Alt+N       | Everywhere                 |
+	| Switch to previous tab  | Alt+B       | Everywhere                 |
+	| Move tab to the right   | Alt+Shift+N | Everywhere                 |
+	| Move tab to the left    | Alt+Shift+B | Everywhere                 |
+	| Split chat              | Alt+V       | Everywhere                 |
+	| Switch chat pane        | Alt+O       | Everywhere                 |
+	| Toggle split direction  | Alt+Shift+V | Everywhere                 |
+	| Show mentions inbox     | Alt+I       | Everywhere                 |
+	| Show quick switcher     | Ctrl+K      | Everywhere                 |
+	| Jump to next unread     | Alt+J       | Everywhere                 |
+	| Jump to next mention    | Alt+Shift+J | Everywhere                 |
+	| Mute or unmute          | Alt+Shift+M | In guild and channel lists |
+	| Mark guild as read      | Alt+Shift+R | In guild list              |
+	----------------------------------------------------------------------
+
+	Channels can be kept open in multiple tabs, which are shown above the
+	chat as soon as there's more than one. Selecting a channel loads it into
+	the current tab, unless it's already open in another tab, in which case
+	that tab is activated. Each tab remembers its selected message, scroll
+	position and unsent message. Tabs that received new messages are
+	highlighted. The open tabs are restored on the next start.
+
+	The chat can be split into two panes, each showing its own channel. The
+	active pane has a highlighted border. Selecting a channel loads it into
+	the active pane and messages are sent to the channel of the active pane.
+	Clicking a pane or switching panes via shortcut activates it. The tabs
+	belong to the first pane.
+
+	Every message that mentions you, one of your roles or @everyone ends up
+	in the mentions inbox, unless you suppressed @everyone for that guild.
+	In the inbox, Enter jumps to the message and d marks the mention as
+	done, removing it from the inbox. The inbox is kept between sessions.
+
+	The quick switcher allows jumping to any guild, channel or private chat
+	by typing parts of its name. Unread and mentioned entries rank higher
+	and the entries you picked most recently are shown first. Up and Down
+	choose an entry, Enter jumps to it and Esc closes the quick switcher.
+
+	Alt+J jumps to the next channel with unread messages and Alt+Shift+J
+	to the next channel with unread mentions. The channels of the current
+	guild are searched first, followed by the other guilds and finally the
+	private chats. Muted guilds and channels are skipped.
+
+	Guilds, categories, channels and private chats can be muted via
+	Alt+Shift+M in the guild list, channel tree or private chat list, or via
+	the mute and unmute commands. Muting asks for how long the mute should
+	last. Muted entries are marked with a speaker symbol and aren't
+	highlighted when receiving new messages. Channels inside of a muted
+	category are muted as well.
+
+	Alt+Shift+R in the guild list marks the selected guild or all guilds of
+	the selected folder as read. The mark-read command can also mark all
+	guilds and private chats as read at once. Since every channel has to be
+	marked separately, this happens gradually in the background.
+
+	Guild folders created in the official client are shown in the guild
+	list. Selecting a folder expands or collapses it. A folder is
+	highlighted if any of its guilds contains unread messages.
+
+	Channels, categories, guilds and folders show badges for unread
+	messages and mentions, for example "(3) @2" for three unread messages,
+	two of which mention you. Unread messages are counted from the start of
+	the session, mentions also include the ones from before. Muted channels
+	only show mentions.
 
 	Some shortcuts can be changed via the shortcut dialog. The dialog can be
 	opened via Alt+Shift+S.`
@@ -0,0 +1,87 @@
+package commandimpls
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/ui"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+const markReadHelpPage = `[::b]NAME
+	mark-read - marks a guild or everything as read
+
+[::b]SYNPOSIS
+	[::b]mark-read[::-] [all|ID|Name[]
+
+[::b]DESCRIPTION
+	This command marks all channels of the given guild as read. If no guild
+	is given, the currently loaded guild is marked as read. Passing [::b]all[::-]
+	marks all guilds and all private chats as read.
+
+	The channels are marked as read one after another in the background, in
+	order to avoid hitting the rate limits of discord.
+
+[::b]EXAMPLES
+	[gray]$ mark-read
+	[gray]$ mark-read "Discord Gophers"
+	[gray]$ mark-read all`
+
+// MarkReadCmd marks whole guilds or everything as read.
+type MarkReadCmd struct {
+	window  *ui.Window
+	session *discordgo.Session
+}
+
+// NewMarkReadCommand creates a command that marks guilds as read.
+func NewMarkReadCommand(window *ui.Window, session *discordgo.Session) *MarkReadCmd {
+	return &MarkReadCmd{window, session}
+}
+
+func (cmd *MarkReadCmd) Execute(writer io.Writer, parameters []string) {
+	if len(parameters) > 1 {
+		cmd.PrintHelp(writer)
+		return
+	}
+
+	var input string
+	if len(parameters) == 1 {
+		input = parameters[0]
+	}
+
+	var channelCount int
+	var markError error
+	if input == "all" {
+		channelCount, markError = cmd.window.MarkAllAsRead()
+	} else {
+		guild, findError := findGuild(cmd.session, cmd.window, input)
+		if findError != nil {
+			fmt.Fprintln(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]"+findError.Error())
+			return
+		}
+
+		channelCount, markError = cmd.window.MarkGuildsAsRead(guild.ID)
+	}
+
+	if markError != nil {
+		fmt.Fprintln(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]"+markError.Error())
+	} else if channelCount == 0 {
+		fmt.Fprintln(writer, "There are no unread channels.")
+	} else {
+		fmt.Fprintf(writer, "Marking %d channels as read.\n", channelCount)
+	}
+}
+
+func (cmd *MarkReadCmd) PrintHelp(writer io.Writer) {
+	fmt.Fprintln(writer, markReadHelpPage)
+}
+
+func (cmd *MarkReadCmd) Name() string {
+	return "mark-read"
+}
+
+func (cmd *MarkReadCmd) Aliases() []string {
+	return []string{"ack"}
+}
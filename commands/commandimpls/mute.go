@@ -0,0 +1,231 @@
+package commandimpls
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/ui"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+const (
+	muteHelpPage = `[::b]NAME
+	mute - mutes a channel, category or guild
+
+[::b]SYNPOSIS
+	[::b]mute[::-] [channel|guild[] [ID|Name[] [-f, --for <DURATION>[]
+
+[::b]DESCRIPTION
+	This command mutes the given channel or guild. Muted channels and guilds
+	aren't highlighted when receiving new messages, but mentions are still
+	counted. Categories can be muted by passing their ID, which also mutes
+	all channels inside of the category.
+
+	If no channel is given, the currently loaded channel is muted. If no
+	guild is given, the currently loaded guild is muted. Channels can also
+	be referred to by their name, in which case the channel is searched for
+	in the currently loaded guild.
+
+	By default, the mute lasts until you unmute. A duration such as 1h or 8h
+	can be passed in order to mute temporarily.
+
+[::b]EXAMPLES
+	[gray]$ mute
+	[gray]$ mute --for 8h
+	[gray]$ mute channel off-topic --for 1h
+	[gray]$ mute guild "Discord Gophers"`
+
+	unmuteHelpPage = `[::b]NAME
+	unmute - unmutes a channel, category or guild
+
+[::b]SYNPOSIS
+	[::b]unmute[::-] [channel|guild[] [ID|Name[]
+
+[::b]DESCRIPTION
+	This command unmutes the given channel or guild. The parameters are the
+	same as for the [::b]mute[::-] command.
+
+[::b]EXAMPLES
+	[gray]$ unmute
+	[gray]$ unmute guild 118456055842734083`
+)
+
+// MuteCmd mutes or unmutes channels and guilds.
+type MuteCmd struct {
+	window  *ui.Window
+	session *discordgo.Session
+	muted   bool
+}
+
+// NewMuteCommand creates a command that mutes channels and guilds.
+func NewMuteCommand(window *ui.Window, session *discordgo.Session) *MuteCmd {
+	return &MuteCmd{window, session, true}
+}
+
+// NewUnmuteCommand creates a command that unmutes channels and guilds.
+func NewUnmuteCommand(window *ui.Window, session *discordgo.Session) *MuteCmd {
+	return &MuteCmd{window, session, false}
+}
+
+func (cmd *MuteCmd) Execute(writer io.Writer, parameters []string) {
+	if cmd.session.State.User.Bot {
+		fmt.Fprintln(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]This command can't be used by bots due to Discord API restrictions.")
+		return
+	}
+
+	var duration time.Duration
+	var remaining []string
+	for index := 0; index < len(parameters); index++ {
+		parameter := parameters[index]
+		if cmd.muted && (parameter == "-f" || parameter == "--for") {
+			if index == len(parameters)-1 {
+				cmd.PrintHelp(writer)
+				return
+			}
+
+			index++
+			var parseError error
+			duration, parseError = time.ParseDuration(parameters[index])
+			if parseError != nil || duration <= 0 {
+				fmt.Fprintf(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]Invalid duration '%s'.\n", parameters[index])
+				return
+			}
+			continue
+		}
+
+		remaining = append(remaining, parameter)
+	}
+
+	targetGuild := false
+	if len(remaining) > 0 {
+		switch remaining[0] {
+		case "guild", "server":
+			targetGuild = true
+			remaining = remaining[1:]
+		case "channel":
+			remaining = remaining[1:]
+		}
+	}
+
+	if len(remaining) > 1 {
+		cmd.PrintHelp(writer)
+		return
+	}
+
+	var input string
+	if len(remaining) == 1 {
+		input = remaining[0]
+	}
+
+	var name string
+	var muteError error
+	if targetGuild {
+		guild, findError := findGuild(cmd.session, cmd.window, input)
+		if findError != nil {
+			fmt.Fprintln(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]"+findError.Error())
+			return
+		}
+
+		name = guild.Name
+		muteError = cmd.window.SetGuildMuted(guild.ID, cmd.muted, duration)
+	} else {
+		channel, findError := cmd.findChannel(input)
+		if findError != nil {
+			fmt.Fprintln(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]"+findError.Error())
+			return
+		}
+
+		if channel.GuildID == "" {
+			name = discordutil.GetPrivateChannelName(channel)
+		} else {
+			name = "#" + channel.Name
+		}
+		muteError = cmd.window.SetChannelMuted(channel, cmd.muted, duration)
+	}
+
+	if muteError != nil {
+		fmt.Fprintf(writer, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]Error changing mute of '%s':\n\t["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]%s\n", name, muteError)
+	} else if !cmd.muted {
+		fmt.Fprintf(writer, "Unmuted '%s'.\n", name)
+	} else if duration > 0 {
+		fmt.Fprintf(writer, "Muted '%s' for %s.\n", name, duration)
+	} else {
+		fmt.Fprintf(writer, "Muted '%s' until you unmute it.\n", name)
+	}
+}
+
+// findChannel returns the channel with the given ID or the channel in the
+// currently loaded guild that has the given name. If the input is empty,
+// the currently loaded channel is returned.
+func (cmd *MuteCmd) findChannel(input string) (*discordgo.Channel, error) {
+	if input == "" {
+		if channel := cmd.window.GetSelectedChannel(); channel != nil {
+			return channel, nil
+		}
+		return nil, fmt.Errorf("No channel is loaded")
+	}
+
+	if channel, stateError := cmd.session.State.Channel(input); stateError == nil {
+		return channel, nil
+	}
+
+	if guild := cmd.window.GetSelectedGuild(); guild != nil {
+		for _, channel := range guild.Channels {
+			if channel.Name == input {
+				return channel, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("No channel with the ID or name '%s' was found", input)
+}
+
+// findGuild returns the guild with the given ID or name. If the input is
+// empty, the currently loaded guild is returned.
+func findGuild(session *discordgo.Session, window *ui.Window, input string) (*discordgo.Guild, error) {
+	if input == "" {
+		if guild := window.GetSelectedGuild(); guild != nil {
+			return guild, nil
+		}
+		return nil, fmt.Errorf("No guild is loaded")
+	}
+
+	var matches []*discordgo.Guild
+	for _, guild := range session.State.Guilds {
+		if guild.ID == input || guild.Name == input {
+			matches = append(matches, guild)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No guild with the ID or name '%s' was found", input)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("Multiple guilds are called '%s'. Please use the ID instead", input)
+	}
+
+	return matches[0], nil
+}
+
+func (cmd *MuteCmd) PrintHelp(writer io.Writer) {
+	if cmd.muted {
+		fmt.Fprintln(writer, muteHelpPage)
+	} else {
+		fmt.Fprintln(writer, unmuteHelpPage)
+	}
+}
+
+func (cmd *MuteCmd) Name() string {
+	if cmd.muted {
+		return "mute"
+	}
+	return "unmute"
+}
+
+func (cmd *MuteCmd) Aliases() []string {
+	return nil
+}
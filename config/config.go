@@ -22,6 +22,14 @@ const (
 	//NoTime means that not time at all will be displayed.
 	NoTime = 2
 
+	// DefaultMessageTemplate is the layout used for messages in the
+	// chatview, unless the user configured a different one.
+	DefaultMessageTemplate = "{time} {author} {content}"
+	// DefaultDateDelimiterFormat is the layout used for the dates shown
+	// between messages of different days, unless the user configured a
+	// different one.
+	DefaultDateDelimiterFormat = "2006-01-02"
+
 	// DoNothingOnTypeInList means that when typing in a list (treeview) simply
 	// nothing will happen.
 	DoNothingOnTypeInList = 0
@@ -32,24 +40,51 @@ const (
 	// FocusMessageInputOnTypeInList will automatically focus the message input
 	// component and transfer the typed character into it as well.
 	FocusMessageInputOnTypeInList = 2
+
+	// RemoveDeletedMessages means that deleted messages disappear from the
+	// chatview immediately.
+	RemoveDeletedMessages = 0
+	// StrikeThroughDeletedMessages keeps deleted messages in the chatview
+	// until the channel is reloaded, striking through their content.
+	StrikeThroughDeletedMessages = 1
+	// DimDeletedMessages keeps deleted messages in the chatview until the
+	// channel is reloaded, dimming their content.
+	DimDeletedMessages = 2
 )
 
 var (
 	currentConfig = Config{
 		Times:                                  HourMinuteAndSeconds,
+		TimeFormat:                             "",
+		RelativeTimes:                          false,
+		Timezone:                               "",
+		DateDelimiterFormat:                    DefaultDateDelimiterFormat,
+		DateLocale:                             "",
 		UseRandomUserColors:                    false,
+		UseRoleColors:                          false,
+		AvoidLowContrastUserColors:             false,
+		MessageTemplate:                        DefaultMessageTemplate,
+		AuthorColumnWidth:                      0,
+		CompactMessages:                        false,
+		MessageGroupingWindow:                  0,
 		ShowUserContainer:                      true,
+		GroupOfflineMembers:                    false,
 		UseFixedLayout:                         false,
 		FixedSizeLeft:                          12,
 		FixedSizeRight:                         12,
+		SplitViewSideBySide:                    true,
 		FocusChannelAfterGuildSelection:        true,
 		FocusMessageInputAfterChannelSelection: true,
 		OnTypeInListBehaviour:                  SearchOnTypeInList,
 		MouseEnabled:                           true,
 		ShortenLinks:                           false,
 		ShortenerPort:                          63212,
+		LinkOpenCommand:                        defaultLinkOpenCommand,
+		DownloadDirectory:                      "~/Downloads",
 		DesktopNotifications:                   true,
+		SendTypingNotifications:                true,
 		ShowPlaceholderForBlockedMessages:      true,
+		DeletedMessageBehaviour:                RemoveDeletedMessages,
 		DontShowUpdateNotificationFor:          "",
 		ShowUpdateNotifications:                true,
 		IndicateChannelAccessRestriction:       false,
@@ -63,9 +98,48 @@ type Config struct {
 
 	//Times decides on the time format (none, short and long).
 	Times int
-	//UseRandomUserColors decides whether the users get assigned a random color
-	//out of a pool for the current session.
+	// TimeFormat is a go time layout for message times. If set, it takes
+	// precedence over Times.
+	TimeFormat string
+	// RelativeTimes shows the times of messages from the last seven days
+	// relative to now, for example "5m ago".
+	RelativeTimes bool
+	// Timezone is the name of the IANA timezone that all times are shown
+	// in, for example "Europe/Berlin". The system timezone is used if empty.
+	Timezone string
+	// DateDelimiterFormat is the go time layout for the dates shown between
+	// messages of different days.
+	DateDelimiterFormat string
+	// DateLocale decides on the language of weekdays and months, for
+	// example "de". English is used if empty.
+	DateLocale string
+	//UseRandomUserColors decides whether the users get assigned a color out
+	//of a pool. The color is derived from the users ID, so it stays the same
+	//across sessions.
 	UseRandomUserColors bool
+	// AvoidLowContrastUserColors decides whether colors that are hard to read
+	// on the PrimitiveBackgroundColor are left out when assigning user
+	// colors. If the background is the terminals default color, it is
+	// assumed to be black.
+	AvoidLowContrastUserColors bool
+	// UseRoleColors decides whether users are coloured with the colour of
+	// their highest coloured role. Users without such a role fall back to
+	// UseRandomUserColors.
+	UseRoleColors bool
+
+	// MessageTemplate defines the layout of a single message in the chatview.
+	// Placeholders like {author} are replaced with the respective values.
+	MessageTemplate string
+	// AuthorColumnWidth right-aligns author names in a column of the given
+	// width. Longer names are shortened. 0 disables the column.
+	AuthorColumnWidth int
+	// CompactMessages hides the time of messages that directly follow a
+	// message of the same author.
+	CompactMessages bool
+	// MessageGroupingWindow is the amount of seconds in which consecutive
+	// messages of the same author are grouped together, only showing the
+	// time and author once. 0 disables grouping.
+	MessageGroupingWindow int
 
 	//FocusChannelAfterGuildSelection will cause the widget focus to move over
 	//to the channel tree after selecting a guild.
@@ -77,6 +151,9 @@ type Config struct {
 	//ShowUserContainer decides whether the user container is part of the
 	//layout or not.
 	ShowUserContainer bool
+	// GroupOfflineMembers decides whether offline members are moved into a
+	// collapsed group at the end of the user list.
+	GroupOfflineMembers bool
 	//UseFixedLayout defines whether the FixedSizeLeft and FixedSizeRight
 	//values will be applied or not.
 	UseFixedLayout bool
@@ -85,6 +162,9 @@ type Config struct {
 	FixedSizeLeft int
 	//FixedSizeRight defines the size of the users container on the right.
 	FixedSizeRight int
+	// SplitViewSideBySide decides whether the two panes of a split chat are
+	// shown next to each other or on top of each other.
+	SplitViewSideBySide bool
 
 	// OnTypeInListBehaviour defines whether the application focus the input
 	// input field on typing, searches the list or does nothing.
@@ -99,9 +179,20 @@ type Config struct {
 	// will be using.
 	ShortenerPort int
 
+	// LinkOpenCommand is the command used for opening links. The
+	// placeholder {url} is replaced with the link. If the command doesn't
+	// contain the placeholder, the link is appended as the last argument.
+	LinkOpenCommand string
+	// DownloadDirectory is the directory that is suggested when downloading
+	// attachments or links.
+	DownloadDirectory string
+
 	// DesktopNotifications decides whether a popup will be shown in the users
 	// system when a notification needs to be sent.
 	DesktopNotifications bool
+	// SendTypingNotifications decides whether other users are told that you
+	// are typing a message.
+	SendTypingNotifications bool
 
 	// ShowPlaceholderForBlockedMessages will cause blocked message to shown
 	// as a placeholder message, replacing user and message with generic text.
@@ -109,6 +200,11 @@ type Config struct {
 	// the timeline of messages.
 	ShowPlaceholderForBlockedMessages bool
 
+	// DeletedMessageBehaviour decides whether deleted messages are removed
+	// from the chatview or kept and marked as deleted until the channel is
+	// reloaded.
+	DeletedMessageBehaviour int
+
 	// ShowUpdateNotifications decides whether update notifications are
 	// shown at all.
 	ShowUpdateNotifications bool
@@ -121,6 +217,12 @@ type Config struct {
 	// switch between the accounts.
 	Accounts []*Account
 
+	// OpenTabs contains the IDs of the channels that were open in tabs when
+	// cordless was closed, so that the tabs can be restored.
+	OpenTabs []string
+	// ActiveTab is the index of the tab in OpenTabs that was active.
+	ActiveTab int
+
 	// Show a padlock prefix of the channels that have access restriction
 	IndicateChannelAccessRestriction bool
 }
@@ -8,6 +8,10 @@ import (
 	"path/filepath"
 )
 
+// defaultLinkOpenCommand opens links using the default application of
+// the system.
+const defaultLinkOpenCommand = "xdg-open {url}"
+
 func getConfigDirectory() (string, error) {
 	configDir := os.Getenv("XDG_CONFIG_DIR")
 
@@ -5,6 +5,10 @@ import (
 	"path/filepath"
 )
 
+// defaultLinkOpenCommand opens links using the default application of
+// the system.
+const defaultLinkOpenCommand = "open {url}"
+
 func getConfigDirectory() (string, error) {
 	// TODO Gotta research this
 
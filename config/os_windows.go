@@ -6,6 +6,10 @@ import (
 	"path/filepath"
 )
 
+// defaultLinkOpenCommand opens links using the default application of
+// the system.
+const defaultLinkOpenCommand = "rundll32 url.dll,FileProtocolHandler {url}"
+
 func getConfigDirectory() (string, error) {
 	configDir := os.Getenv("APPDATA")
 
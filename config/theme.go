@@ -23,7 +23,26 @@ type Theme struct {
 	LinkColor        tcell.Color
 	AttentionColor   tcell.Color
 	ErrorColor       tcell.Color
+	InlineCodeColor  tcell.Color
 	RandomUserColors []tcell.Color
+
+	FindMatchColor        tcell.Color
+	CurrentFindMatchColor tcell.Color
+
+	DiffAddedColor   tcell.Color
+	DiffRemovedColor tcell.Color
+
+	OnlineColor       tcell.Color
+	IdleColor         tcell.Color
+	DoNotDisturbColor tcell.Color
+	OfflineColor      tcell.Color
+
+	// SyntaxHighlightingStyle is the name of the chroma style used for
+	// highlighting code blocks.
+	SyntaxHighlightingStyle string
+	// SyntaxHighlightingFormatter is the name of the chroma formatter used
+	// for highlighting code blocks. Either tview-8bit or tview-truecolor.
+	SyntaxHighlightingFormatter string
 }
 
 var (
@@ -51,14 +70,25 @@ func createDefaultTheme() *Theme {
 			InverseTextColor:            tcell.ColorBlue,
 			ContrastSecondaryTextColor:  tcell.ColorDarkCyan,
 		},
-		BlockedUserColor: tcell.ColorGray,
-		InfoMessageColor: tcell.ColorGray,
-		BotColor:         tcell.NewRGBColor(0x94, 0x96, 0xfc),
-		MessageTimeColor: tcell.ColorGray,
-		LinkColor:        tcell.ColorDarkCyan,
-		DefaultUserColor: tcell.NewRGBColor(0x44, 0xe5, 0x44),
-		AttentionColor:   tcell.ColorOrange,
-		ErrorColor:       tcell.ColorRed,
+		BlockedUserColor:            tcell.ColorGray,
+		InfoMessageColor:            tcell.ColorGray,
+		BotColor:                    tcell.NewRGBColor(0x94, 0x96, 0xfc),
+		MessageTimeColor:            tcell.ColorGray,
+		LinkColor:                   tcell.ColorDarkCyan,
+		DefaultUserColor:            tcell.NewRGBColor(0x44, 0xe5, 0x44),
+		AttentionColor:              tcell.ColorOrange,
+		ErrorColor:                  tcell.ColorRed,
+		InlineCodeColor:             tcell.ColorSilver,
+		FindMatchColor:              tcell.ColorOlive,
+		CurrentFindMatchColor:       tcell.ColorOrange,
+		DiffAddedColor:              tcell.ColorGreen,
+		DiffRemovedColor:            tcell.ColorRed,
+		OnlineColor:                 tcell.ColorGreen,
+		IdleColor:                   tcell.ColorYellow,
+		DoNotDisturbColor:           tcell.ColorRed,
+		OfflineColor:                tcell.ColorGray,
+		SyntaxHighlightingStyle:     "monokai",
+		SyntaxHighlightingFormatter: "tview-8bit",
 		RandomUserColors: []tcell.Color{
 			tcell.NewRGBColor(0xd8, 0x50, 0x4e),
 			tcell.NewRGBColor(0xd8, 0x7e, 0x4e),
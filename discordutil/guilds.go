@@ -1,6 +1,7 @@
 package discordutil
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/Bios-Marcel/discordgo"
@@ -54,3 +55,67 @@ func SortGuilds(settings *discordgo.Settings, guilds []*discordgo.Guild) {
 		return false
 	})
 }
+
+// GuildFolder is a folder of guilds, as created by the official client.
+// Guilds that aren't part of any folder are represented by a folder without
+// an ID, containing only that guild.
+type GuildFolder struct {
+	ID       *int64   `json:"id"`
+	Name     string   `json:"name"`
+	Color    *int     `json:"color"`
+	GuildIDs []string `json:"guild_ids"`
+}
+
+// IsFolder decides whether the folder is an actual folder or just a single
+// guild that isn't part of a folder.
+func (folder *GuildFolder) IsFolder() bool {
+	return folder.ID != nil
+}
+
+// SettingsRequester reflects an instance that allows doing requests against
+// a discord backend. This is required, since the user settings of discordgo
+// don't contain guild folders.
+type SettingsRequester interface {
+	RequestWithBucketID(method, urlStr string, data interface{}, bucketID string) ([]byte, error)
+}
+
+// LoadGuildFolders loads the guild folders out of the current users settings.
+func LoadGuildFolders(requester SettingsRequester) ([]*GuildFolder, error) {
+	body, discordError := requester.RequestWithBucketID("GET", discordgo.EndpointUserSettings("@me"), nil, discordgo.EndpointUserSettings(""))
+	if discordError != nil {
+		return nil, discordError
+	}
+
+	var settings struct {
+		GuildFolders []*GuildFolder `json:"guild_folders"`
+	}
+	parseError := json.Unmarshal(body, &settings)
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	return settings.GuildFolders, nil
+}
+
+// ParseGuildFolders returns the guild folders contained in an update of the
+// users settings. If the folders haven't changed, false is returned.
+func ParseGuildFolders(event *discordgo.UserSettingsUpdate) ([]*GuildFolder, bool) {
+	rawFolders, contains := (*event)["guild_folders"]
+	if !contains {
+		return nil, false
+	}
+
+	//The event has already been parsed into a map, therefore the folders
+	//have to be converted back into JSON first.
+	folderJSON, jsonError := json.Marshal(rawFolders)
+	if jsonError != nil {
+		return nil, false
+	}
+
+	var folders []*GuildFolder
+	if json.Unmarshal(folderJSON, &folders) != nil {
+		return nil, false
+	}
+
+	return folders, true
+}
@@ -169,3 +169,60 @@ func TestLoadGuilds(t *testing.T) {
 		})
 	}
 }
+
+type testSettingsRequester struct {
+	body string
+	err  error
+}
+
+func (requester testSettingsRequester) RequestWithBucketID(method, urlStr string, data interface{}, bucketID string) ([]byte, error) {
+	return []byte(requester.body), requester.err
+}
+
+func TestLoadGuildFolders(t *testing.T) {
+	folders, loadError := LoadGuildFolders(testSettingsRequester{body: `{
+		"locale": "en-US",
+		"guild_folders": [
+			{"id": null, "name": null, "color": null, "guild_ids": ["1"]},
+			{"id": 1234, "name": "Work", "color": 16711680, "guild_ids": ["2", "3"]}
+		]
+	}`})
+	if loadError != nil {
+		t.Fatalf("Unexpected error: %s", loadError)
+	}
+
+	if len(folders) != 2 {
+		t.Fatalf("Expected 2 folders, but got %d", len(folders))
+	}
+	if folders[0].IsFolder() || !reflect.DeepEqual(folders[0].GuildIDs, []string{"1"}) {
+		t.Errorf("The first entry should've been the single guild 1, but was %+v", folders[0])
+	}
+	if !folders[1].IsFolder() || folders[1].Name != "Work" || *folders[1].Color != 0xff0000 ||
+		!reflect.DeepEqual(folders[1].GuildIDs, []string{"2", "3"}) {
+		t.Errorf("The second entry should've been the folder Work, but was %+v", folders[1])
+	}
+
+	_, loadError = LoadGuildFolders(testSettingsRequester{err: errors.New("unauthorized")})
+	if loadError == nil {
+		t.Error("Expected the request error to be returned")
+	}
+}
+
+func TestParseGuildFolders(t *testing.T) {
+	_, changed := ParseGuildFolders(&discordgo.UserSettingsUpdate{"theme": "dark"})
+	if changed {
+		t.Error("Settings without folders shouldn't have been treated as a change of folders")
+	}
+
+	folders, changed := ParseGuildFolders(&discordgo.UserSettingsUpdate{
+		"guild_folders": []interface{}{
+			map[string]interface{}{"id": 1234.0, "name": "Games", "color": nil, "guild_ids": []interface{}{"1"}},
+		},
+	})
+	if !changed || len(folders) != 1 {
+		t.Fatalf("Expected one folder, but got %v", folders)
+	}
+	if *folders[0].ID != 1234 || folders[0].Name != "Games" || folders[0].Color != nil {
+		t.Errorf("The folder wasn't parsed correctly: %+v", folders[0])
+	}
+}
@@ -13,3 +13,59 @@ func MentionsCurrentUserExplicitly(state *discordgo.State, message *discordgo.Me
 
 	return false
 }
+
+// MentionsCurrentUser checks whether the message mentions the currently
+// logged in user in any way. Besides explicit mentions, this includes
+// mentions of roles that the user has and mentions of @everyone and @here,
+// unless the user has suppressed those for the guild. Messages written by the
+// user, for example their own @everyone, never count as mentions.
+func MentionsCurrentUser(state *discordgo.State, message *discordgo.Message) bool {
+	if message.Author != nil && message.Author.ID == state.User.ID {
+		return false
+	}
+
+	if MentionsCurrentUserExplicitly(state, message) {
+		return true
+	}
+
+	//Roles and @everyone only exist in guilds.
+	if message.GuildID == "" {
+		return false
+	}
+
+	if message.MentionEveryone && !isEveryoneSuppressed(state, message.GuildID) {
+		return true
+	}
+
+	if len(message.MentionRoles) == 0 {
+		return false
+	}
+
+	member, cacheError := state.Member(message.GuildID, state.User.ID)
+	if cacheError != nil {
+		return false
+	}
+
+	for _, mentionedRoleID := range message.MentionRoles {
+		for _, roleID := range member.Roles {
+			if roleID == mentionedRoleID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isEveryoneSuppressed(state *discordgo.State, guildID string) bool {
+	state.RLock()
+	defer state.RUnlock()
+
+	for _, settings := range state.UserGuildSettings {
+		if settings.GetGuildID() == guildID {
+			return settings.SupressEveryone
+		}
+	}
+
+	return false
+}
@@ -96,3 +96,80 @@ func Test_MentionsCurrentUserExplicitly(t *testing.T) {
 		})
 	}
 }
+
+func Test_MentionsCurrentUser(t *testing.T) {
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: "123"}
+	state.UserGuildSettings = []*discordgo.UserGuildSettings{
+		{GuildID: "suppressed", SupressEveryone: true},
+	}
+	for _, guildID := range []string{"guild", "suppressed"} {
+		state.GuildAdd(&discordgo.Guild{
+			ID: guildID,
+			Members: []*discordgo.Member{
+				{User: &discordgo.User{ID: "123"}, GuildID: guildID, Roles: []string{"own-role"}},
+			},
+		})
+	}
+
+	tests := []struct {
+		name    string
+		message *discordgo.Message
+		want    bool
+	}{
+		{
+			name:    "no mentions",
+			message: &discordgo.Message{GuildID: "guild"},
+			want:    false,
+		}, {
+			name: "explicit mention in private channel",
+			message: &discordgo.Message{
+				Mentions: []*discordgo.User{{ID: "123"}},
+			},
+			want: true,
+		}, {
+			name:    "everyone",
+			message: &discordgo.Message{GuildID: "guild", MentionEveryone: true},
+			want:    true,
+		}, {
+			name:    "everyone suppressed",
+			message: &discordgo.Message{GuildID: "suppressed", MentionEveryone: true},
+			want:    false,
+		}, {
+			name:    "explicit mention with everyone suppressed",
+			message: &discordgo.Message{GuildID: "suppressed", MentionEveryone: true, Mentions: []*discordgo.User{{ID: "123"}}},
+			want:    true,
+		}, {
+			name:    "own role",
+			message: &discordgo.Message{GuildID: "guild", MentionRoles: []string{"other-role", "own-role"}},
+			want:    true,
+		}, {
+			name:    "foreign role",
+			message: &discordgo.Message{GuildID: "guild", MentionRoles: []string{"other-role"}},
+			want:    false,
+		}, {
+			name:    "own everyone",
+			message: &discordgo.Message{GuildID: "guild", MentionEveryone: true, Author: &discordgo.User{ID: "123"}},
+			want:    false,
+		}, {
+			name:    "own explicit mention",
+			message: &discordgo.Message{GuildID: "guild", Mentions: []*discordgo.User{{ID: "123"}}, Author: &discordgo.User{ID: "123"}},
+			want:    false,
+		}, {
+			name:    "everyone by someone else",
+			message: &discordgo.Message{GuildID: "guild", MentionEveryone: true, Author: &discordgo.User{ID: "1"}},
+			want:    true,
+		}, {
+			name:    "role in unknown guild",
+			message: &discordgo.Message{GuildID: "unknown", MentionRoles: []string{"own-role"}},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MentionsCurrentUser(state, tt.message); got != tt.want {
+				t.Errorf("MentionsCurrentUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package discordutil
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+// muteConfig tells discord how long a mute lasts. The settings of discordgo
+// don't contain it, which is why the requests are built by hand.
+type muteConfig struct {
+	EndTime *time.Time `json:"end_time"`
+	// SelectedTimeWindow is the duration in seconds, or -1 if the mute
+	// lasts until the user unmutes.
+	SelectedTimeWindow int `json:"selected_time_window"`
+}
+
+// rawMuteSettings are the parts of the user guild settings that tell whether
+// and until when the guild and its channels are muted.
+type rawMuteSettings struct {
+	GuildID          string      `json:"guild_id"`
+	Muted            bool        `json:"muted"`
+	MuteConfig       *muteConfig `json:"mute_config"`
+	ChannelOverrides []struct {
+		ChannelID  string      `json:"channel_id"`
+		Muted      bool        `json:"muted"`
+		MuteConfig *muteConfig `json:"mute_config"`
+	} `json:"channel_overrides"`
+}
+
+func getEndTime(muted bool, config *muteConfig) time.Time {
+	if !muted || config == nil || config.EndTime == nil {
+		return time.Time{}
+	}
+
+	return *config.EndTime
+}
+
+func (settings *rawMuteSettings) addMuteEndTimes(endTimes map[string]time.Time) {
+	//The settings for private channels don't belong to a guild.
+	if settings.GuildID != "" {
+		endTimes[settings.GuildID] = getEndTime(settings.Muted, settings.MuteConfig)
+	}
+	for _, override := range settings.ChannelOverrides {
+		endTimes[override.ChannelID] = getEndTime(override.Muted, override.MuteConfig)
+	}
+}
+
+// GetMuteEndTimes parses the times at which the mutes of a guild and its
+// channels expire out of the raw user guild settings, as sent with
+// USER_GUILD_SETTINGS_UPDATE. The times are mapped to the guild and channel
+// IDs. Guilds and channels that aren't muted or whose mute lasts until the
+// user unmutes are mapped to the zero time.
+func GetMuteEndTimes(rawSettings []byte) (map[string]time.Time, error) {
+	var settings rawMuteSettings
+	parseError := json.Unmarshal(rawSettings, &settings)
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	endTimes := make(map[string]time.Time)
+	settings.addMuteEndTimes(endTimes)
+	return endTimes, nil
+}
+
+// GetReadyMuteEndTimes works like GetMuteEndTimes, but parses the user guild
+// settings of all guilds contained in the raw READY event.
+func GetReadyMuteEndTimes(rawReady []byte) (map[string]time.Time, error) {
+	var ready struct {
+		UserGuildSettings []*rawMuteSettings `json:"user_guild_settings"`
+	}
+	parseError := json.Unmarshal(rawReady, &ready)
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	endTimes := make(map[string]time.Time)
+	for _, settings := range ready.UserGuildSettings {
+		settings.addMuteEndTimes(endTimes)
+	}
+	return endTimes, nil
+}
+
+// GetMuteEndTime returns the time at which a mute with the given duration
+// started at the given time expires. A duration of zero or less means that
+// the mute lasts until the user unmutes, in which case the zero time is
+// returned.
+func GetMuteEndTime(start time.Time, duration time.Duration) time.Time {
+	if duration <= 0 {
+		return time.Time{}
+	}
+
+	return start.Add(duration)
+}
+
+func newMuteConfig(muted bool, duration time.Duration) *muteConfig {
+	if !muted {
+		return nil
+	}
+
+	endTime := GetMuteEndTime(time.Now(), duration)
+	if endTime.IsZero() {
+		return &muteConfig{SelectedTimeWindow: -1}
+	}
+
+	return &muteConfig{EndTime: &endTime, SelectedTimeWindow: int(duration.Seconds())}
+}
+
+// SetGuildMuted mutes or unmutes the guild with the given ID. The mute
+// expires after the given duration, unless the duration is zero. The
+// updated settings for the guild are returned.
+func SetGuildMuted(requester SettingsRequester, guildID string, muted bool, duration time.Duration) (*discordgo.UserGuildSettings, error) {
+	return editUserGuildSettings(requester, guildID, map[string]interface{}{
+		"muted":       muted,
+		"mute_config": newMuteConfig(muted, duration),
+	})
+}
+
+// SetChannelMuted mutes or unmutes the given channel, which might also be
+// a category or a private channel. The mute expires after the given
+// duration, unless the duration is zero. The updated settings for the guild
+// of the channel are returned.
+func SetChannelMuted(requester SettingsRequester, channel *discordgo.Channel, muted bool, duration time.Duration) (*discordgo.UserGuildSettings, error) {
+	//The settings for private channels are saved as the settings of the
+	//guild "@me".
+	guildID := channel.GuildID
+	if guildID == "" {
+		guildID = "@me"
+	}
+
+	return editUserGuildSettings(requester, guildID, map[string]interface{}{
+		"channel_overrides": map[string]interface{}{
+			channel.ID: map[string]interface{}{
+				"muted":       muted,
+				"mute_config": newMuteConfig(muted, duration),
+			},
+		},
+	})
+}
+
+func editUserGuildSettings(requester SettingsRequester, guildID string, data map[string]interface{}) (*discordgo.UserGuildSettings, error) {
+	body, discordError := requester.RequestWithBucketID("PATCH",
+		discordgo.EndpointUserGuildSettings("@me", guildID), data, discordgo.EndpointUserGuildSettings("", guildID))
+	if discordError != nil {
+		return nil, discordError
+	}
+
+	var settings *discordgo.UserGuildSettings
+	parseError := json.Unmarshal(body, &settings)
+	if parseError != nil {
+		return nil, parseError
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,129 @@
+package discordutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+type recordingSettingsRequester struct {
+	method string
+	urlStr string
+	data   interface{}
+	body   string
+}
+
+func (requester *recordingSettingsRequester) RequestWithBucketID(method, urlStr string, data interface{}, bucketID string) ([]byte, error) {
+	requester.method = method
+	requester.urlStr = urlStr
+	requester.data = data
+	return []byte(requester.body), nil
+}
+
+func TestSetGuildMuted(t *testing.T) {
+	requester := &recordingSettingsRequester{body: `{"guild_id": "1", "muted": true}`}
+	settings, muteError := SetGuildMuted(requester, "1", true, 0)
+	if muteError != nil {
+		t.Fatalf("Unexpected error: %s", muteError)
+	}
+	if !settings.Muted || settings.GetGuildID() != "1" {
+		t.Errorf("Unexpected settings: %+v", settings)
+	}
+	if requester.method != "PATCH" || requester.urlStr != discordgo.EndpointUserGuildSettings("@me", "1") {
+		t.Errorf("Unexpected request: %s %s", requester.method, requester.urlStr)
+	}
+
+	data, _ := json.Marshal(requester.data)
+	if string(data) != `{"mute_config":{"end_time":null,"selected_time_window":-1},"muted":true}` {
+		t.Errorf("Unexpected request data: %s", data)
+	}
+
+	SetGuildMuted(requester, "1", false, 0)
+	data, _ = json.Marshal(requester.data)
+	if string(data) != `{"mute_config":null,"muted":false}` {
+		t.Errorf("Unexpected request data: %s", data)
+	}
+}
+
+func TestSetChannelMuted(t *testing.T) {
+	requester := &recordingSettingsRequester{body: `{"guild_id": null, "channel_overrides": [{"channel_id": "2", "muted": true}]}`}
+	settings, muteError := SetChannelMuted(requester, &discordgo.Channel{ID: "2"}, true, time.Hour)
+	if muteError != nil {
+		t.Fatalf("Unexpected error: %s", muteError)
+	}
+	if settings.GetGuildID() != "" || len(settings.ChannelOverrides) != 1 || !settings.ChannelOverrides[0].Muted {
+		t.Errorf("Unexpected settings: %+v", settings)
+	}
+	if requester.urlStr != discordgo.EndpointUserGuildSettings("@me", "@me") {
+		t.Errorf("Private channels should be muted via the settings of @me, but %s was used", requester.urlStr)
+	}
+
+	override := requester.data.(map[string]interface{})["channel_overrides"].(map[string]interface{})["2"].(map[string]interface{})
+	config := override["mute_config"].(*muteConfig)
+	if config.SelectedTimeWindow != 3600 || config.EndTime == nil || config.EndTime.Before(time.Now()) {
+		t.Errorf("Unexpected mute config: %+v", config)
+	}
+}
+
+func TestGetMuteEndTime(t *testing.T) {
+	start := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	if endTime := GetMuteEndTime(start, 8*time.Hour); !endTime.Equal(start.Add(8 * time.Hour)) {
+		t.Errorf("GetMuteEndTime() = %s", endTime)
+	}
+	if endTime := GetMuteEndTime(start, 0); !endTime.IsZero() {
+		t.Errorf("GetMuteEndTime() = %s, want the zero time", endTime)
+	}
+}
+
+func TestGetMuteEndTimes(t *testing.T) {
+	endTimes, parseError := GetMuteEndTimes([]byte(`{
+		"guild_id": "1",
+		"muted": true,
+		"mute_config": {"end_time": "2019-10-10T10:00:00Z", "selected_time_window": 3600},
+		"channel_overrides": [
+			{"channel_id": "2", "muted": true, "mute_config": {"end_time": null, "selected_time_window": -1}},
+			{"channel_id": "3", "muted": false, "mute_config": {"end_time": "2019-10-10T10:00:00Z", "selected_time_window": 3600}},
+			{"channel_id": "4", "muted": true}
+		]
+	}`))
+	if parseError != nil {
+		t.Fatalf("Unexpected error: %s", parseError)
+	}
+
+	want := map[string]time.Time{
+		"1": time.Date(2019, 10, 10, 10, 0, 0, 0, time.UTC),
+		"2": {},
+		"3": {},
+		"4": {},
+	}
+	if len(endTimes) != len(want) {
+		t.Errorf("Expected %d end times, but got %v", len(want), endTimes)
+	}
+	for id, wantedEndTime := range want {
+		if endTime, contains := endTimes[id]; !contains || !endTime.Equal(wantedEndTime) {
+			t.Errorf("End time of %s is %v, want %v", id, endTime, wantedEndTime)
+		}
+	}
+}
+
+func TestGetReadyMuteEndTimes(t *testing.T) {
+	endTimes, parseError := GetReadyMuteEndTimes([]byte(`{
+		"user_guild_settings": [
+			{"guild_id": "1", "muted": true, "mute_config": {"end_time": "2019-10-10T10:00:00Z", "selected_time_window": 3600}},
+			{"guild_id": null, "muted": false, "channel_overrides": [
+				{"channel_id": "2", "muted": true, "mute_config": {"end_time": "2019-10-11T10:00:00Z", "selected_time_window": 3600}}
+			]}
+		]
+	}`))
+	if parseError != nil {
+		t.Fatalf("Unexpected error: %s", parseError)
+	}
+
+	if len(endTimes) != 2 ||
+		!endTimes["1"].Equal(time.Date(2019, 10, 10, 10, 0, 0, 0, time.UTC)) ||
+		!endTimes["2"].Equal(time.Date(2019, 10, 11, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected end times: %v", endTimes)
+	}
+}
@@ -1,57 +1,73 @@
 package discordutil
 
 import (
-	"math/rand"
+	"hash/fnv"
 	"sort"
 
 	"github.com/Bios-Marcel/cordless/config"
 	"github.com/Bios-Marcel/cordless/ui/tviewutil"
 	"github.com/Bios-Marcel/discordgo"
 	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// minimumUserColorContrast is the contrast ratio that user colours need
+	// to have against the background if AvoidLowContrastUserColors is set.
+	// This is the minimum that the WCAG recommends for large text.
+	minimumUserColorContrast = 3.0
 )
 
 var (
 	botPrefix = tview.Escape("[BOT]")
-
-	//global state that persist during a session.
-	userColorCache = make(map[string]string)
-
-	lastRandomNumber = -1
 )
 
-// GetUserColor gets the users color for this session. If no color can be found
-// a new color will be a generated and cached.
+// GetUserColor gets the users color. The color is derived from the users ID,
+// therefore each user always gets the same color, as long as the configured
+// colors don't change.
 func GetUserColor(user *discordgo.User) string {
 	if user.Bot {
 		return tviewutil.ColorToHex(config.GetTheme().BotColor)
 	}
 
-	color, ok := userColorCache[user.ID]
-	if ok {
-		return color
+	userColors := getUserColors()
+	if len(userColors) == 0 {
+		return tviewutil.ColorToHex(config.GetTheme().DefaultUserColor)
 	}
 
-	newColor := getRandomColorString()
-	userColorCache[user.ID] = newColor
-	return newColor
+	hash := fnv.New32a()
+	//Writing to a hash never returns an error.
+	hash.Write([]byte(user.ID))
+	return tviewutil.ColorToHex(userColors[hash.Sum32()%uint32(len(userColors))])
 }
 
-func getRandomColorString() string {
-	randColorLength := len(config.GetTheme().RandomUserColors)
-	if randColorLength == 0 {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().DefaultUserColor) + "]"
-	} else if randColorLength == 1 {
-		return tviewutil.ColorToHex(config.GetTheme().RandomUserColors[0])
+// getUserColors returns the colors that can be assigned to users. If
+// AvoidLowContrastUserColors is set, colors that are hard to read on the
+// background are left out, unless that would leave no colors at all. Since
+// the actual color of tcell.ColorDefault is up to the terminal, a black
+// background is assumed for it, as that's what most terminals use.
+func getUserColors() []tcell.Color {
+	userColors := config.GetTheme().RandomUserColors
+	if !config.GetConfig().AvoidLowContrastUserColors {
+		return userColors
 	}
 
-	randIndex := rand.Intn(randColorLength)
-	if randIndex == lastRandomNumber {
-		return getRandomColorString()
+	backgroundColor := config.GetTheme().PrimitiveBackgroundColor
+	if backgroundColor == tcell.ColorDefault {
+		backgroundColor = tcell.ColorBlack
+	}
+	readableColors := make([]tcell.Color, 0, len(userColors))
+	for _, color := range userColors {
+		if tviewutil.ContrastRatio(color, backgroundColor) >= minimumUserColorContrast {
+			readableColors = append(readableColors, color)
+		}
 	}
 
-	lastRandomNumber = randIndex
+	if len(readableColors) == 0 {
+		return userColors
+	}
 
-	return tviewutil.ColorToHex(config.GetTheme().RandomUserColors[randIndex])
+	return readableColors
 }
 
 // GetMemberName returns the name to use for representing this user. This is
@@ -115,6 +131,25 @@ func SortUserRoles(roles []string, guildRoles []*discordgo.Role) {
 	})
 }
 
+// GetRoleColor returns the colour of the highest role of the member that
+// has a colour. If none of the roles has a colour, 0 is returned, which is
+// what discord uses for roles without a colour as well.
+func GetRoleColor(member *discordgo.Member, guildRoles []*discordgo.Role) int {
+	roles := make([]string, len(member.Roles))
+	copy(roles, member.Roles)
+	SortUserRoles(roles, guildRoles)
+
+	for _, roleID := range roles {
+		for _, role := range guildRoles {
+			if role.ID == roleID && role.Color != 0 {
+				return role.Color
+			}
+		}
+	}
+
+	return 0
+}
+
 // IsBlocked checks whether the state contains any relationship that says the
 // given user has been blocked.
 func IsBlocked(state *discordgo.State, user *discordgo.User) bool {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Bios-Marcel/cordless/config"
 	"github.com/Bios-Marcel/discordgo"
+	"github.com/gdamore/tcell"
 )
 
 func TestGetUserColor(t *testing.T) {
@@ -62,6 +63,63 @@ func TestGetUserColor(t *testing.T) {
 	}
 }
 
+func TestGetUserColor_stable(t *testing.T) {
+	tests := []struct {
+		userID string
+		want   string
+	}{
+		{userID: "1398541219874", want: "#4e57d8"},
+		{userID: "0183587135982", want: "#d8c64e"},
+		{userID: "155417194530996225", want: "#d8c64e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				if got := GetUserColor(&discordgo.User{ID: tt.userID}); got != tt.want {
+					t.Errorf("GetUserColor() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUserColor_avoidLowContrast(t *testing.T) {
+	theme := config.GetTheme()
+	oldUserColors, oldBackgroundColor := theme.RandomUserColors, theme.PrimitiveBackgroundColor
+	oldAvoidLowContrast := config.GetConfig().AvoidLowContrastUserColors
+	defer func() {
+		theme.RandomUserColors, theme.PrimitiveBackgroundColor = oldUserColors, oldBackgroundColor
+		config.GetConfig().AvoidLowContrastUserColors = oldAvoidLowContrast
+	}()
+
+	darkGray := tcell.NewRGBColor(0x20, 0x20, 0x20)
+	theme.RandomUserColors = []tcell.Color{darkGray, tcell.NewRGBColor(0xff, 0xff, 0xff)}
+	theme.PrimitiveBackgroundColor = tcell.NewRGBColor(0, 0, 0)
+
+	user := &discordgo.User{ID: "1"}
+	config.GetConfig().AvoidLowContrastUserColors = false
+	if got := GetUserColor(user); got != tviewutil.ColorToHex(darkGray) {
+		t.Errorf("GetUserColor() = %v, want %v", got, tviewutil.ColorToHex(darkGray))
+	}
+
+	config.GetConfig().AvoidLowContrastUserColors = true
+	if got := GetUserColor(user); got != "#ffffff" {
+		t.Errorf("GetUserColor() = %v, want #ffffff", got)
+	}
+
+	//The terminals default background is assumed to be black.
+	theme.PrimitiveBackgroundColor = tcell.ColorDefault
+	if got := GetUserColor(user); got != "#ffffff" {
+		t.Errorf("GetUserColor() = %v, want #ffffff", got)
+	}
+
+	//If no color is readable, all colors are used anyway.
+	theme.RandomUserColors = []tcell.Color{darkGray}
+	if got := GetUserColor(user); got != tviewutil.ColorToHex(darkGray) {
+		t.Errorf("GetUserColor() = %v, want %v", got, tviewutil.ColorToHex(darkGray))
+	}
+}
+
 func TestGetMemberName(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -280,6 +338,46 @@ func TestSortUserRoles(t *testing.T) {
 	}
 }
 
+func TestGetRoleColor(t *testing.T) {
+	guildRoles := []*discordgo.Role{
+		{ID: "uncolored", Position: 3},
+		{ID: "high", Position: 2, Color: 0xff0000},
+		{ID: "low", Position: 1, Color: 0x00ff00},
+	}
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  int
+	}{
+		{
+			name:  "no roles",
+			roles: []string{},
+			want:  0,
+		}, {
+			name:  "only uncolored roles",
+			roles: []string{"uncolored", "unknown"},
+			want:  0,
+		}, {
+			name:  "highest colored role wins",
+			roles: []string{"low", "uncolored", "high"},
+			want:  0xff0000,
+		}, {
+			name:  "lower colored role",
+			roles: []string{"uncolored", "low"},
+			want:  0x00ff00,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member := &discordgo.Member{Roles: tt.roles}
+			if got := GetRoleColor(member, guildRoles); got != tt.want {
+				t.Errorf("GetRoleColor() = %06x, want %06x", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsBlocked(t *testing.T) {
 	type args struct {
 		state *discordgo.State
@@ -0,0 +1,319 @@
+// Package markdown implements a parser for the markdown dialect that discord
+// uses. The parser produces a tree of nodes that can either be rendered into
+// any kind of output or be turned back into the exact markdown it was parsed
+// from.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NodeType decides what kind of formatting a Node represents.
+type NodeType int
+
+const (
+	// Text is plain text without any formatting.
+	Text NodeType = iota
+	// Escaped is a single character that has been escaped using a backslash.
+	Escaped
+	// Bold is text surrounded by **.
+	Bold
+	// Italic is text surrounded by either * or _.
+	Italic
+	// Underline is text surrounded by __.
+	Underline
+	// Strikethrough is text surrounded by ~~.
+	Strikethrough
+	// Spoiler is text surrounded by ||.
+	Spoiler
+	// InlineCode is text surrounded by either ` or ``.
+	InlineCode
+	// CodeBlock is text surrounded by ```, optionally specifying a language.
+	CodeBlock
+	// BlockQuote is either a single line starting with "> " or everything
+	// following ">>> ".
+	BlockQuote
+)
+
+var urlRegex = regexp.MustCompile(`^(<https?://[^\s>]+>|https?://[^\s<]+[^<.,:;"')\]\s])`)
+
+// Node is a single element of a parsed markdown text.
+type Node struct {
+	Type NodeType
+	// Content is the raw text of Text, Escaped, InlineCode and CodeBlock
+	// nodes. All other nodes use Children instead.
+	Content string
+	// Language is the language specified for a CodeBlock. It's empty if no
+	// language has been specified.
+	Language string
+	// Delimiter is the markdown that opened this node, for example "**" or
+	// "```go\n".
+	Delimiter string
+	// Children contains the nested nodes of all formatting nodes.
+	Children []*Node
+}
+
+// ClosingDelimiter returns the markdown that closes this node. BlockQuotes,
+// Text and Escaped nodes don't have a closing delimiter.
+func (node *Node) ClosingDelimiter() string {
+	switch node.Type {
+	case Text, Escaped, BlockQuote:
+		return ""
+	case CodeBlock:
+		return "```"
+	default:
+		return node.Delimiter
+	}
+}
+
+// Markdown turns the node back into the markdown it was parsed from.
+func (node *Node) Markdown() string {
+	return node.Delimiter + node.Content + Markdown(node.Children) + node.ClosingDelimiter()
+}
+
+// Markdown turns the nodes back into the markdown they were parsed from.
+func Markdown(nodes []*Node) string {
+	var builder strings.Builder
+	for _, node := range nodes {
+		builder.WriteString(node.Markdown())
+	}
+	return builder.String()
+}
+
+// Parse turns the given text into a tree of nodes. Parsing never fails, text
+// that isn't valid markdown is treated as plain text.
+func Parse(text string) []*Node {
+	return parse(text, true)
+}
+
+func parse(text string, allowBlockQuotes bool) []*Node {
+	var nodes []*Node
+	textStart := 0
+	flushText := func(end int) {
+		if end > textStart {
+			nodes = append(nodes, &Node{Type: Text, Content: text[textStart:end]})
+		}
+	}
+
+	for index := 0; index < len(text); {
+		node, length := parseNode(text, index, allowBlockQuotes)
+		if node == nil {
+			//URLs are taken as they are, so that their content isn't
+			//mistaken as formatting.
+			if url := urlRegex.FindString(text[index:]); url != "" {
+				index += len(url)
+			} else {
+				_, size := utf8.DecodeRuneInString(text[index:])
+				index += size
+			}
+			continue
+		}
+
+		flushText(index)
+		nodes = append(nodes, node)
+		index += length
+		textStart = index
+	}
+	flushText(len(text))
+
+	return nodes
+}
+
+// parseNode attempts parsing a node at the given index. If no node could be
+// parsed, nil is returned. Otherwise the node and the amount of bytes of the
+// text that it covers are returned.
+func parseNode(text string, index int, allowBlockQuotes bool) (*Node, int) {
+	remaining := text[index:]
+	lineStart := index == 0 || text[index-1] == '\n'
+
+	if allowBlockQuotes && lineStart {
+		if strings.HasPrefix(remaining, ">>> ") {
+			return &Node{
+				Type:      BlockQuote,
+				Delimiter: ">>> ",
+				Children:  parse(remaining[4:], false),
+			}, len(remaining)
+		}
+
+		if strings.HasPrefix(remaining, "> ") {
+			lineEnd := strings.IndexByte(remaining, '\n')
+			if lineEnd == -1 {
+				lineEnd = len(remaining)
+			}
+			return &Node{
+				Type:      BlockQuote,
+				Delimiter: "> ",
+				Children:  parse(remaining[2:lineEnd], false),
+			}, lineEnd
+		}
+	}
+
+	switch remaining[0] {
+	case '\\':
+		if len(remaining) > 1 && isEscapable(remaining[1]) {
+			return &Node{Type: Escaped, Delimiter: "\\", Content: remaining[1:2]}, 2
+		}
+	case '`':
+		if strings.HasPrefix(remaining, "```") {
+			if node, length := parseCodeBlock(remaining); node != nil {
+				return node, length
+			}
+		}
+		if strings.HasPrefix(remaining, "``") {
+			if node, length := parseInlineCode(remaining, "``"); node != nil {
+				return node, length
+			}
+		}
+		return parseInlineCode(remaining, "`")
+	case '|':
+		return parseDelimited(remaining, "||", Spoiler, isAnyClosing)
+	case '~':
+		return parseDelimited(remaining, "~~", Strikethrough, isAnyClosing)
+	case '*':
+		if node, length := parseDelimited(remaining, "**", Bold, isClosingNotFollowedBy('*')); node != nil {
+			return node, length
+		}
+		//Italic text mustn't start or end with whitespace.
+		if len(remaining) > 1 && !isSpace(remaining[1]) {
+			return parseDelimited(remaining, "*", Italic, func(text string, closingIndex int) bool {
+				return !isSpace(text[closingIndex-1])
+			})
+		}
+	case '_':
+		if node, length := parseDelimited(remaining, "__", Underline, isClosingNotFollowedBy('_')); node != nil {
+			return node, length
+		}
+		//Underscores inside of words, like in snake_case, aren't formatting.
+		if index == 0 || !isWordCharacter(text[index-1]) {
+			return parseDelimited(remaining, "_", Italic, func(text string, closingIndex int) bool {
+				return closingIndex+1 == len(text) || !isWordCharacter(text[closingIndex+1])
+			})
+		}
+	}
+
+	return nil, 0
+}
+
+// parseDelimited parses a node that starts and ends with the given delimiter.
+// The isClosing function decides whether an occurrence of the delimiter
+// closes the node.
+func parseDelimited(text, delimiter string, nodeType NodeType, isClosing func(text string, closingIndex int) bool) (*Node, int) {
+	if !strings.HasPrefix(text, delimiter) {
+		return nil, 0
+	}
+
+	closingIndex := findClosingDelimiter(text, len(delimiter), delimiter, isClosing)
+	if closingIndex == -1 {
+		return nil, 0
+	}
+
+	//Content that consists of the delimiter only, like in "****", isn't
+	//treated as formatting.
+	content := text[len(delimiter):closingIndex]
+	if strings.Trim(content, delimiter[:1]) == "" {
+		return nil, 0
+	}
+
+	return &Node{
+		Type:      nodeType,
+		Delimiter: delimiter,
+		Children:  parse(content, false),
+	}, closingIndex + len(delimiter)
+}
+
+// findClosingDelimiter searches for the first occurrence of the delimiter
+// that isn't escaped and satisfies isClosing. Single character delimiters
+// skip doubled occurrences, as those belong to different formatting.
+func findClosingDelimiter(text string, start int, delimiter string, isClosing func(text string, closingIndex int) bool) int {
+	for index := start; index < len(text); index++ {
+		if text[index] == '\\' {
+			index++
+			continue
+		}
+
+		if !strings.HasPrefix(text[index:], delimiter) {
+			continue
+		}
+
+		if len(delimiter) == 1 && index+1 < len(text) && text[index+1] == delimiter[0] {
+			index++
+			continue
+		}
+
+		if isClosing(text, index) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func isAnyClosing(text string, closingIndex int) bool {
+	return true
+}
+
+// isClosingNotFollowedBy makes sure that sequences like "***" are closed by
+// the last two characters, allowing italic text inside of bold text.
+func isClosingNotFollowedBy(character byte) func(text string, closingIndex int) bool {
+	return func(text string, closingIndex int) bool {
+		return closingIndex+2 >= len(text) || text[closingIndex+2] != character
+	}
+}
+
+func parseInlineCode(text, delimiter string) (*Node, int) {
+	closingIndex := strings.Index(text[len(delimiter):], delimiter)
+	if closingIndex <= 0 {
+		return nil, 0
+	}
+
+	return &Node{
+		Type:      InlineCode,
+		Delimiter: delimiter,
+		Content:   text[len(delimiter) : len(delimiter)+closingIndex],
+	}, closingIndex + 2*len(delimiter)
+}
+
+func parseCodeBlock(text string) (*Node, int) {
+	closingIndex := strings.Index(text[3:], "```")
+	if closingIndex <= 0 {
+		return nil, 0
+	}
+
+	content := text[3 : 3+closingIndex]
+	node := &Node{
+		Type:      CodeBlock,
+		Delimiter: "```",
+		Content:   content,
+	}
+
+	//The first line is the language, as long as it's a single word.
+	if newLineIndex := strings.IndexByte(content, '\n'); newLineIndex != -1 {
+		language := content[:newLineIndex]
+		if !strings.ContainsAny(language, " \t") {
+			node.Language = strings.TrimSuffix(language, "\r")
+			node.Delimiter = "```" + language + "\n"
+			node.Content = content[newLineIndex+1:]
+		}
+	}
+
+	return node, closingIndex + 6
+}
+
+// isEscapable checks whether the character can be escaped with a backslash.
+// Letters, digits and whitespace can't be escaped.
+func isEscapable(character byte) bool {
+	return character < utf8.RuneSelf &&
+		(unicode.IsPunct(rune(character)) || unicode.IsSymbol(rune(character)))
+}
+
+func isSpace(character byte) bool {
+	return character == ' ' || character == '\t' || character == '\n' || character == '\r'
+}
+
+func isWordCharacter(character byte) bool {
+	return character >= utf8.RuneSelf ||
+		unicode.IsLetter(rune(character)) || unicode.IsDigit(rune(character))
+}
@@ -0,0 +1,215 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"simple",
+		"simple\nsimple",
+		"**Hallo Welt**",
+		"****Hallo Welt",
+		"**Hallo Welt",
+		"**Hallo\nWelt**",
+		"Hallo**\nWelt**",
+		"Hal**lo\nWelt**",
+		"__Hallo Welt__",
+		"____Hallo Welt",
+		"__Hallo Welt",
+		"__Hallo\nWelt__",
+		"Hallo__\nWelt__",
+		"Hal__lo\nWelt__",
+		"**__Hallo Welt__**",
+		"** OwO__Hallo Welt__**",
+		"** OwO__Hallo Welt__** What",
+		"a **fat__simple__fat** b",
+		"a __underline**fat**underline__ b",
+		"a __underline**fatunderline__ b",
+		"||simple||",
+		"gimme ||**simple**|| pls",
+		"owo ||spoiler",
+		"gimme **||simple||** pls",
+		"```\none\ntwo\nthree\n```",
+		"test\n```\none\n```\ntest",
+		"test```\none\n```test",
+		"```go\none\n```",
+		"```cpp\none\n\n\n```",
+		"```\none\n```\n```\none\n```",
+		"```\nowo ||Spoiler|| owo\n```",
+		"||```\nowo\n```||",
+		"```\nowo\n```f```\nowo\n```",
+		"\\`\\*\\_",
+		"\\\\`\\*\\_",
+		"*italic* _italic_ ~~strike~~ `code` ``co`de``",
+		"> quote\n>>> rest\nof the message",
+		"https://example.com/some_path_with__underscores__",
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			if got := Markdown(Parse(input)); got != input {
+				t.Errorf("Markdown(Parse()) = '%v', want '%v'", got, input)
+			}
+		})
+	}
+}
+
+func text(content string) *Node {
+	return &Node{Type: Text, Content: content}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []*Node
+	}{
+		{
+			name:  "plain text",
+			input: "Hallo Welt",
+			want:  []*Node{text("Hallo Welt")},
+		}, {
+			name:  "unclosed bold",
+			input: "**Hallo Welt",
+			want:  []*Node{text("**Hallo Welt")},
+		}, {
+			name:  "underline inside bold",
+			input: "a **fat__simple__** b",
+			want: []*Node{
+				text("a "),
+				{Type: Bold, Delimiter: "**", Children: []*Node{
+					text("fat"),
+					{Type: Underline, Delimiter: "__", Children: []*Node{text("simple")}},
+				}},
+				text(" b"),
+			},
+		}, {
+			name:  "italic inside bold",
+			input: "***both***",
+			want: []*Node{
+				{Type: Bold, Delimiter: "**", Children: []*Node{
+					{Type: Italic, Delimiter: "*", Children: []*Node{text("both")}},
+				}},
+			},
+		}, {
+			name:  "bold inside italic",
+			input: "*a **b** c*",
+			want: []*Node{
+				{Type: Italic, Delimiter: "*", Children: []*Node{
+					text("a "),
+					{Type: Bold, Delimiter: "**", Children: []*Node{text("b")}},
+					text(" c"),
+				}},
+			},
+		}, {
+			name:  "multiplication isn't italic",
+			input: "5 * 3 * 2",
+			want:  []*Node{text("5 * 3 * 2")},
+		}, {
+			name:  "snake case isn't italic",
+			input: "snake_case_name",
+			want:  []*Node{text("snake_case_name")},
+		}, {
+			name:  "underscore italic",
+			input: "_italic_",
+			want: []*Node{
+				{Type: Italic, Delimiter: "_", Children: []*Node{text("italic")}},
+			},
+		}, {
+			name:  "strikethrough",
+			input: "~~gone~~",
+			want: []*Node{
+				{Type: Strikethrough, Delimiter: "~~", Children: []*Node{text("gone")}},
+			},
+		}, {
+			name:  "spoiler with formatting inside",
+			input: "||**a**||",
+			want: []*Node{
+				{Type: Spoiler, Delimiter: "||", Children: []*Node{
+					{Type: Bold, Delimiter: "**", Children: []*Node{text("a")}},
+				}},
+			},
+		}, {
+			name:  "inline code isn't formatted",
+			input: "`**a**`",
+			want: []*Node{
+				{Type: InlineCode, Delimiter: "`", Content: "**a**"},
+			},
+		}, {
+			name:  "inline code with backtick inside",
+			input: "``a`b``",
+			want: []*Node{
+				{Type: InlineCode, Delimiter: "``", Content: "a`b"},
+			},
+		}, {
+			name:  "codeblock with language",
+			input: "```go\nfunc main() {}\n```",
+			want: []*Node{
+				{Type: CodeBlock, Delimiter: "```go\n", Language: "go", Content: "func main() {}\n"},
+			},
+		}, {
+			name:  "codeblock without newline",
+			input: "```code```",
+			want: []*Node{
+				{Type: CodeBlock, Delimiter: "```", Content: "code"},
+			},
+		}, {
+			name:  "escaped characters",
+			input: "\\*a\\*",
+			want: []*Node{
+				{Type: Escaped, Delimiter: "\\", Content: "*"},
+				text("a"),
+				{Type: Escaped, Delimiter: "\\", Content: "*"},
+			},
+		}, {
+			name:  "escaped closing delimiter",
+			input: "**a\\**",
+			want: []*Node{
+				text("*"),
+				{Type: Italic, Delimiter: "*", Children: []*Node{
+					text("a"),
+					{Type: Escaped, Delimiter: "\\", Content: "*"},
+				}},
+			},
+		}, {
+			name:  "backslash before letter",
+			input: "C:\\path",
+			want:  []*Node{text("C:\\path")},
+		}, {
+			name:  "single line quotes",
+			input: "> a\n> b\nc",
+			want: []*Node{
+				{Type: BlockQuote, Delimiter: "> ", Children: []*Node{text("a")}},
+				text("\n"),
+				{Type: BlockQuote, Delimiter: "> ", Children: []*Node{text("b")}},
+				text("\nc"),
+			},
+		}, {
+			name:  "quote not at line start",
+			input: "a > b",
+			want:  []*Node{text("a > b")},
+		}, {
+			name:  "multi line quote",
+			input: ">>> a\n**b**",
+			want: []*Node{
+				{Type: BlockQuote, Delimiter: ">>> ", Children: []*Node{
+					text("a\n"),
+					{Type: Bold, Delimiter: "**", Children: []*Node{text("b")}},
+				}},
+			},
+		}, {
+			name:  "url with underscores",
+			input: "see https://example.com/__init__ there",
+			want:  []*Node{text("see https://example.com/__init__ there")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", Markdown(got), Markdown(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package mentions
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Bios-Marcel/cordless/config"
+)
+
+// maxMentions is the amount of mentions that the inbox keeps. Once the inbox
+// is full, the oldest mentions are dropped.
+const maxMentions = 500
+
+var (
+	mutex    = &sync.Mutex{}
+	mentions = make([]*Mention, 0)
+)
+
+// Mention is a message that mentioned the current user. Guild and channel
+// names are saved as well, since they can't be looked up anymore if the
+// user left the guild or the channel has been deleted.
+type Mention struct {
+	MessageID   string
+	ChannelID   string
+	GuildID     string
+	GuildName   string
+	ChannelName string
+	AuthorName  string
+	Content     string
+	// Timestamp is the time the message was sent at, in the format the
+	// Discord API uses.
+	Timestamp string
+}
+
+// Add puts a mention at the end of the inbox. If the message is already
+// part of the inbox, it's not added a second time. The return value
+// indicates whether the inbox has changed.
+func Add(mention *Mention) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, existingMention := range mentions {
+		if existingMention.MessageID == mention.MessageID {
+			return false
+		}
+	}
+
+	mentions = append(mentions, mention)
+	if len(mentions) > maxMentions {
+		mentions = mentions[len(mentions)-maxMentions:]
+	}
+
+	return true
+}
+
+// MarkDone removes the mention for the given message from the inbox. The
+// return value indicates whether the inbox has changed.
+func MarkDone(messageID string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for index, mention := range mentions {
+		if mention.MessageID == messageID {
+			mentions = append(mentions[:index], mentions[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMentions returns all mentions in the inbox, starting with the newest
+// one.
+func GetMentions() []*Mention {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	newestFirst := make([]*Mention, 0, len(mentions))
+	for index := len(mentions) - 1; index >= 0; index-- {
+		newestFirst = append(newestFirst, mentions[index])
+	}
+
+	return newestFirst
+}
+
+func getMentionsPath() (string, error) {
+	configDirectory, configError := config.GetConfigDirectory()
+	if configError != nil {
+		return "", configError
+	}
+
+	return filepath.Join(configDirectory, "mentions.json"), nil
+}
+
+// Load loads the inbox that has been saved by the previous session.
+func Load() error {
+	mentionsPath, pathError := getMentionsPath()
+	if pathError != nil {
+		return pathError
+	}
+
+	mentionsFile, openError := os.Open(mentionsPath)
+
+	if os.IsNotExist(openError) {
+		return nil
+	}
+
+	if openError != nil {
+		return openError
+	}
+
+	defer mentionsFile.Close()
+	decoder := json.NewDecoder(mentionsFile)
+	loadedMentions := make([]*Mention, 0)
+	mentionsLoadError := decoder.Decode(&loadedMentions)
+
+	//io.EOF would mean empty, therefore we start with an empty inbox.
+	if mentionsLoadError != nil && mentionsLoadError != io.EOF {
+		return mentionsLoadError
+	}
+
+	mutex.Lock()
+	mentions = loadedMentions
+	mutex.Unlock()
+
+	return nil
+}
+
+// Persist saves the inbox, so that it can be loaded on the next start.
+func Persist() error {
+	filePath, pathError := getMentionsPath()
+	if pathError != nil {
+		return pathError
+	}
+
+	mutex.Lock()
+	mentionsAsJSON, jsonError := json.MarshalIndent(&mentions, "", "    ")
+	mutex.Unlock()
+	if jsonError != nil {
+		return jsonError
+	}
+
+	return ioutil.WriteFile(filePath, mentionsAsJSON, 0666)
+}
@@ -0,0 +1,54 @@
+package mentions
+
+import (
+	"strconv"
+	"testing"
+)
+
+func getMessageIDs() []string {
+	messageIDs := make([]string, 0)
+	for _, mention := range GetMentions() {
+		messageIDs = append(messageIDs, mention.MessageID)
+	}
+	return messageIDs
+}
+
+func TestInbox(t *testing.T) {
+	mentions = make([]*Mention, 0)
+
+	if !Add(&Mention{MessageID: "1"}) || !Add(&Mention{MessageID: "2"}) {
+		t.Error("new mentions haven't been added")
+	}
+	if Add(&Mention{MessageID: "1"}) {
+		t.Error("mention has been added twice")
+	}
+	if messageIDs := getMessageIDs(); len(messageIDs) != 2 || messageIDs[0] != "2" || messageIDs[1] != "1" {
+		t.Errorf("GetMentions() = %v, want [2 1]", messageIDs)
+	}
+
+	if !MarkDone("2") {
+		t.Error("mention hasn't been marked as done")
+	}
+	if MarkDone("2") {
+		t.Error("mention has been marked as done twice")
+	}
+	if messageIDs := getMessageIDs(); len(messageIDs) != 1 || messageIDs[0] != "1" {
+		t.Errorf("GetMentions() = %v, want [1]", messageIDs)
+	}
+}
+
+func TestInbox_dropsOldestMentions(t *testing.T) {
+	mentions = make([]*Mention, 0)
+
+	for index := 0; index < maxMentions+2; index++ {
+		Add(&Mention{MessageID: strconv.Itoa(index)})
+	}
+
+	messageIDs := getMessageIDs()
+	if len(messageIDs) != maxMentions {
+		t.Fatalf("inbox contains %d mentions, want %d", len(messageIDs), maxMentions)
+	}
+	if messageIDs[len(messageIDs)-1] != "2" {
+		t.Errorf("oldest mention is %s, want 2", messageIDs[len(messageIDs)-1])
+	}
+}
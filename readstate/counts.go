@@ -0,0 +1,107 @@
+package readstate
+
+import (
+	"sync"
+
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+var (
+	countMutex    = &sync.Mutex{}
+	unreadCounts  = make(map[string]int)
+	mentionCounts = make(map[string]int)
+)
+
+// loadMentionCounts takes over the mention counts that discord sent us on
+// startup. Discord doesn't tell us how many messages are unread, therefore
+// unread messages are only counted from here on. Counts of a previous
+// connection are dropped, since the read state replaces them.
+func loadMentionCounts() {
+	countMutex.Lock()
+	defer countMutex.Unlock()
+
+	unreadCounts = make(map[string]int)
+	mentionCounts = make(map[string]int)
+	for _, channelState := range state.ReadState {
+		if channelState.MentionCount > 0 {
+			mentionCounts[channelState.ID] = channelState.MentionCount
+		}
+	}
+}
+
+// AddUnreadMessage counts a new unread message in the given channel. If the
+// message mentions the current user, it's counted as a mention as well.
+func AddUnreadMessage(channelID string, mentioned bool) {
+	countMutex.Lock()
+	defer countMutex.Unlock()
+
+	unreadCounts[channelID]++
+	if mentioned {
+		mentionCounts[channelID]++
+	}
+}
+
+// clearCounts resets the counters of the given channel, since it has been
+// read.
+func clearCounts(channelID string) {
+	countMutex.Lock()
+	defer countMutex.Unlock()
+
+	delete(unreadCounts, channelID)
+	delete(mentionCounts, channelID)
+}
+
+// GetUnreadCount returns the amount of unread messages in the given channel.
+func GetUnreadCount(channelID string) int {
+	countMutex.Lock()
+	defer countMutex.Unlock()
+
+	return unreadCounts[channelID]
+}
+
+// GetMentionCount returns the amount of unread messages in the given channel
+// that mention the current user.
+func GetMentionCount(channelID string) int {
+	countMutex.Lock()
+	defer countMutex.Unlock()
+
+	return mentionCounts[channelID]
+}
+
+// GetChannelCounts returns the amount of unread messages and mentions in the
+// given channel. Unread messages in muted channels aren't counted, but
+// mentions are.
+func GetChannelCounts(channel *discordgo.Channel) (unread int, mentions int) {
+	unread, mentions = GetUnreadCount(channel.ID), GetMentionCount(channel.ID)
+	if unread > 0 && IsChannelMuted(channel) {
+		unread = 0
+	}
+
+	return unread, mentions
+}
+
+// GetGuildCounts sums up the unread messages and mentions of all channels in
+// the given guild that the current user can read. Unread messages in muted
+// channels or guilds aren't counted, but mentions are.
+func GetGuildCounts(guildID string) (unread int, mentions int) {
+	guild, cacheError := state.Guild(guildID)
+	if cacheError != nil {
+		return 0, 0
+	}
+
+	guildMuted := IsGuildMuted(guildID)
+	for _, channel := range guild.Channels {
+		if !discordutil.HasReadMessagesPermission(channel.ID, state) {
+			continue
+		}
+
+		channelUnread, channelMentions := GetChannelCounts(channel)
+		if !guildMuted {
+			unread += channelUnread
+		}
+		mentions += channelMentions
+	}
+
+	return unread, mentions
+}
@@ -0,0 +1,62 @@
+package readstate
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func TestCounts(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.User = &discordgo.User{ID: "U1"}
+	sessionState.Ready = discordgo.Ready{
+		ReadState: []*discordgo.ReadState{
+			{ID: "C1", LastMessageID: "1", MentionCount: 2},
+		},
+	}
+	Load(sessionState)
+
+	if mentions := GetMentionCount("C1"); mentions != 2 {
+		t.Errorf("GetMentionCount() = %d, want 2", mentions)
+	}
+
+	AddUnreadMessage("C1", false)
+	AddUnreadMessage("C1", true)
+	if unread := GetUnreadCount("C1"); unread != 2 {
+		t.Errorf("GetUnreadCount() = %d, want 2", unread)
+	}
+	if mentions := GetMentionCount("C1"); mentions != 3 {
+		t.Errorf("GetMentionCount() = %d, want 3", mentions)
+	}
+
+	//Older acknowledgements don't reset the counts.
+	UpdateReadLocal("C1", "0")
+	if unread := GetUnreadCount("C1"); unread != 2 {
+		t.Errorf("GetUnreadCount() = %d, want 2", unread)
+	}
+
+	UpdateReadLocal("C1", "3")
+	if unread, mentions := GetUnreadCount("C1"), GetMentionCount("C1"); unread != 0 || mentions != 0 {
+		t.Errorf("counts = (%d, %d), want (0, 0)", unread, mentions)
+	}
+}
+
+func TestCountsAfterReconnect(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.Ready = discordgo.Ready{
+		ReadState: []*discordgo.ReadState{
+			{ID: "C1", LastMessageID: "1", MentionCount: 2},
+		},
+	}
+	Load(sessionState)
+	AddUnreadMessage("C2", true)
+
+	//Loading the same read state again mustn't add up the counts.
+	Load(sessionState)
+	if mentions := GetMentionCount("C1"); mentions != 2 {
+		t.Errorf("GetMentionCount() = %d, want 2", mentions)
+	}
+	if unread, mentions := GetUnreadCount("C2"), GetMentionCount("C2"); unread != 0 || mentions != 0 {
+		t.Errorf("counts = (%d, %d), want (0, 0)", unread, mentions)
+	}
+}
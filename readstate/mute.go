@@ -0,0 +1,85 @@
+package readstate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+var (
+	muteMutex = &sync.Mutex{}
+	// muteEndTimes contains the times at which mutes of guilds and channels
+	// expire. Mutes that last until the user unmutes aren't contained.
+	muteEndTimes = make(map[string]time.Time)
+)
+
+// SetMuteEndTime remembers when the mute of the guild or channel with the
+// given ID expires. A zero time means that the mute doesn't expire.
+func SetMuteEndTime(id string, endTime time.Time) {
+	muteMutex.Lock()
+	defer muteMutex.Unlock()
+
+	if endTime.IsZero() {
+		delete(muteEndTimes, id)
+	} else {
+		muteEndTimes[id] = endTime
+	}
+}
+
+// UpdateMuteEndTimes takes over the end times of mutes that discord sent
+// us, for example via GetMuteEndTimes. Zero times remove the previously
+// known end time, since the mute has either been lifted or lasts until the
+// user unmutes.
+func UpdateMuteEndTimes(endTimes map[string]time.Time) {
+	muteMutex.Lock()
+	defer muteMutex.Unlock()
+
+	for id, endTime := range endTimes {
+		if endTime.IsZero() {
+			delete(muteEndTimes, id)
+		} else {
+			muteEndTimes[id] = endTime
+		}
+	}
+}
+
+// GetMuteEndTimes returns the end times of all mutes that expire, mapped to
+// the IDs of the muted guilds and channels.
+func GetMuteEndTimes() map[string]time.Time {
+	muteMutex.Lock()
+	defer muteMutex.Unlock()
+
+	endTimes := make(map[string]time.Time, len(muteEndTimes))
+	for id, endTime := range muteEndTimes {
+		endTimes[id] = endTime
+	}
+	return endTimes
+}
+
+func isMuteExpired(id string) bool {
+	muteMutex.Lock()
+	defer muteMutex.Unlock()
+
+	endTime, hasEndTime := muteEndTimes[id]
+	return hasEndTime && !time.Now().Before(endTime)
+}
+
+// UpdateGuildSettings replaces the current users settings for the guild
+// the given settings belong to. This affects whether guilds and channels
+// are considered muted.
+func UpdateGuildSettings(settings *discordgo.UserGuildSettings) {
+	state.Lock()
+	defer state.Unlock()
+
+	guildID := settings.GetGuildID()
+	newSettings := make([]*discordgo.UserGuildSettings, 0, len(state.UserGuildSettings)+1)
+	for _, oldSettings := range state.UserGuildSettings {
+		//There might be multiple instances for private channels.
+		if oldSettings.GetGuildID() != guildID {
+			newSettings = append(newSettings, oldSettings)
+		}
+	}
+
+	state.UserGuildSettings = append(newSettings, settings)
+}
@@ -0,0 +1,101 @@
+package readstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func TestMutes(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.Ready = discordgo.Ready{
+		User: &discordgo.User{ID: "U1"},
+		UserGuildSettings: []*discordgo.UserGuildSettings{
+			{GuildID: "G1", Muted: true},
+		},
+	}
+	Load(sessionState)
+
+	channel := &discordgo.Channel{ID: "C1", GuildID: "G2", ParentID: "K1"}
+	if !IsGuildMuted("G1") || IsGuildMuted("G2") || IsChannelMuted(channel) {
+		t.Fatal("Only G1 should be muted initially")
+	}
+
+	UpdateGuildSettings(&discordgo.UserGuildSettings{
+		GuildID: "G2",
+		ChannelOverrides: []*discordgo.UserGuildSettingsChannelOverride{
+			{ChannelID: "K1", Muted: true},
+		},
+	})
+	if !IsChannelMuted(channel) {
+		t.Error("Channels should inherit the mute of their category")
+	}
+
+	SetMuteEndTime("K1", time.Now().Add(-time.Minute))
+	defer SetMuteEndTime("K1", time.Time{})
+	if IsChannelMuted(channel) {
+		t.Error("Expired mutes should be ignored")
+	}
+
+	UpdateGuildSettings(&discordgo.UserGuildSettings{GuildID: "G1"})
+	if IsGuildMuted("G1") {
+		t.Error("G1 should have been unmuted")
+	}
+	if len(sessionState.UserGuildSettings) != 2 {
+		t.Errorf("Expected 2 settings, but got %d", len(sessionState.UserGuildSettings))
+	}
+}
+
+func TestUpdateMuteEndTimes(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.Ready = discordgo.Ready{
+		User: &discordgo.User{ID: "U1"},
+		UserGuildSettings: []*discordgo.UserGuildSettings{
+			{GuildID: "G1", Muted: true},
+		},
+	}
+	Load(sessionState)
+	defer UpdateMuteEndTimes(map[string]time.Time{"G1": {}})
+
+	//A mute that has already expired.
+	UpdateMuteEndTimes(map[string]time.Time{"G1": time.Now().Add(-time.Minute)})
+	if IsGuildMuted("G1") {
+		t.Error("G1 should be considered unmuted, since its mute has expired")
+	}
+
+	//Muting again without an end time must remove the old end time.
+	UpdateMuteEndTimes(map[string]time.Time{"G1": {}})
+	if !IsGuildMuted("G1") {
+		t.Error("G1 should be muted until the user unmutes")
+	}
+	if _, contains := GetMuteEndTimes()["G1"]; contains {
+		t.Error("The end time of G1 should have been removed")
+	}
+
+	UpdateMuteEndTimes(map[string]time.Time{"G1": time.Now().Add(time.Hour)})
+	if !IsGuildMuted("G1") {
+		t.Error("G1 should be muted until its mute expires")
+	}
+}
+
+func TestMutes_concurrentSettingsUpdates(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.Ready = discordgo.Ready{User: &discordgo.User{ID: "U1"}}
+	Load(sessionState)
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			UpdateGuildSettings(&discordgo.UserGuildSettings{GuildID: "G1", Muted: i%2 == 0})
+		}
+		close(done)
+	}()
+
+	channel := &discordgo.Channel{ID: "C1", GuildID: "G1"}
+	for i := 0; i < 100; i++ {
+		IsGuildMuted("G1")
+		IsChannelMuted(channel)
+	}
+	<-done
+}
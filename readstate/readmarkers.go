@@ -10,14 +10,18 @@ import (
 )
 
 var (
-	data       = make(map[string]uint64)
-	timerMutex = &sync.Mutex{}
-	ackTimers  = make(map[string]*time.Timer)
-	state      *discordgo.State
+	data = make(map[string]uint64)
+	// readStateMutex guards data, since it's written from event handlers
+	// and read from the UI.
+	readStateMutex = &sync.RWMutex{}
+	timerMutex     = &sync.Mutex{}
+	ackTimers      = make(map[string]*time.Timer)
+	state          *discordgo.State
 )
 
 // Load loads the locally saved readmarkers returing an error if this failed.
 func Load(sessionState *discordgo.State) {
+	readStateMutex.Lock()
 	for _, channelState := range sessionState.ReadState {
 		lastMessageID := channelState.GetLastMessageID()
 		if lastMessageID == "" {
@@ -31,16 +35,37 @@ func Load(sessionState *discordgo.State) {
 
 		data[channelState.ID] = parsed
 	}
+	readStateMutex.Unlock()
 
 	state = sessionState
+	loadMentionCounts()
 }
 
 // ClearReadStateFor clears all entries for the given Channel.
 func ClearReadStateFor(channelID string) {
-	timerMutex.Lock()
+	readStateMutex.Lock()
 	delete(data, channelID)
+	readStateMutex.Unlock()
+
+	timerMutex.Lock()
 	delete(ackTimers, channelID)
 	timerMutex.Unlock()
+
+	clearCounts(channelID)
+}
+
+// GetLastReadMessageID returns the ID of the last message that has been read
+// in the given channel. If the channel has never been read, an empty string
+// is returned.
+func GetLastReadMessageID(channelID string) string {
+	readStateMutex.RLock()
+	lastMessageID, present := data[channelID]
+	readStateMutex.RUnlock()
+	if !present {
+		return ""
+	}
+
+	return strconv.FormatUint(lastMessageID, 10)
 }
 
 // UpdateReadLocal can be used to locally update the data without sending
@@ -52,33 +77,71 @@ func UpdateReadLocal(channelID string, lastMessageID string) bool {
 		return false
 	}
 
+	readStateMutex.Lock()
 	old, isPresent := data[channelID]
-	if !isPresent || old < parsed {
+	updated := !isPresent || old < parsed
+	if updated {
 		data[channelID] = parsed
-		return true
 	}
+	readStateMutex.Unlock()
 
-	return false
+	if updated {
+		clearCounts(channelID)
+	}
+
+	return updated
 }
 
 // UpdateRead tells the discord server that a channel has been read. If the
 // channel has already been read and this method was called needlessly, then
 // this will be a No-OP.
 func UpdateRead(session *discordgo.Session, channel *discordgo.Channel, lastMessageID string) error {
+	// Muted channels are always considered read, but might still have
+	// mentions that need to be cleared.
+	clearCounts(channel.ID)
+
 	// Avoid unnecessary traffic
 	if HasBeenRead(channel, lastMessageID) {
 		return nil
 	}
 
+	return Acknowledge(session, channel, lastMessageID)
+}
+
+// Acknowledge tells the discord server that a channel has been read up to
+// the given message, even if the channel is muted. This is necessary in
+// order to clear mentions in muted channels. The local state is only updated
+// once the server has accepted the acknowledgement.
+func Acknowledge(session *discordgo.Session, channel *discordgo.Channel, lastMessageID string) error {
 	parsed, parseError := strconv.ParseUint(lastMessageID, 10, 64)
 	if parseError != nil {
 		return parseError
 	}
 
-	data[channel.ID] = parsed
-
 	_, ackError := session.ChannelMessageAck(channel.ID, lastMessageID, "")
-	return ackError
+	if ackError != nil {
+		return ackError
+	}
+
+	readStateMutex.Lock()
+	if parsed > data[channel.ID] {
+		data[channel.ID] = parsed
+	}
+	readStateMutex.Unlock()
+	clearCounts(channel.ID)
+
+	return nil
+}
+
+// NeedsAck decides whether the channel contains messages that haven't been
+// acknowledged yet. Unlike HasBeenRead, this also applies to muted channels
+// that contain unread mentions.
+func NeedsAck(channel *discordgo.Channel) bool {
+	if channel.LastMessageID == "" {
+		return false
+	}
+
+	return !HasBeenRead(channel, channel.LastMessageID) || GetMentionCount(channel.ID) > 0
 }
 
 // UpdateReadBuffered triggers an acknowledgement after a certain amount of
@@ -101,11 +164,21 @@ func UpdateReadBuffered(session *discordgo.Session, channel *discordgo.Channel,
 	timerMutex.Unlock()
 }
 
+// getUserGuildSettings returns the current users settings for all guilds.
+// Since UpdateGuildSettings replaces the slice instead of modifying it, the
+// result can be used without holding the lock of the state.
+func getUserGuildSettings() []*discordgo.UserGuildSettings {
+	state.RLock()
+	defer state.RUnlock()
+
+	return state.UserGuildSettings
+}
+
 // IsGuildMuted returns whether the user muted the given guild.
 func IsGuildMuted(guildID string) bool {
-	for _, settings := range state.UserGuildSettings {
+	for _, settings := range getUserGuildSettings() {
 		if settings.GuildID == guildID {
-			if settings.Muted {
+			if settings.Muted && !isMuteExpired(guildID) {
 				return true
 			}
 
@@ -140,18 +213,30 @@ func HasGuildBeenRead(guildID string) bool {
 }
 
 // IsChannelMuted checks whether the channel is muted or not. This works for
-// private channels as well as for guild channels. The reasoning for this is,
-// that discord saves all private channel settings in the settings object for
-// the Guild with the GuildID emtpy.
+// private channels as well as for guild channels. Channels that are part of
+// a muted category are muted as well.
 func IsChannelMuted(channel *discordgo.Channel) bool {
+	if isChannelOverrideMuted(channel.GuildID, channel.ID) {
+		return true
+	}
+
+	return channel.ParentID != "" && isChannelOverrideMuted(channel.GuildID, channel.ParentID)
+}
+
+// isChannelOverrideMuted checks whether the user muted the channel with the
+// given ID. The reasoning for the guildID is, that discord saves all private
+// channel settings in the settings object for the Guild with the GuildID
+// emtpy.
+func isChannelOverrideMuted(guildID, channelID string) bool {
+	userGuildSettings := getUserGuildSettings()
 	//optimization for the case of guild channels, as the handling for
 	//private channels will be unnecessarily slower.
-	if channel.GuildID == "" {
-		for _, settings := range state.UserGuildSettings {
-			if settings.GetGuildID() == channel.GuildID {
+	if guildID == "" {
+		for _, settings := range userGuildSettings {
+			if settings.GetGuildID() == guildID {
 				for _, override := range settings.ChannelOverrides {
-					if override.ChannelID == channel.ID {
-						if override.Muted {
+					if override.ChannelID == channelID {
+						if override.Muted && !isMuteExpired(channelID) {
 							return true
 						}
 
@@ -165,11 +250,11 @@ func IsChannelMuted(channel *discordgo.Channel) bool {
 			}
 		}
 	} else {
-		for _, settings := range state.UserGuildSettings {
-			if settings.GetGuildID() == channel.GuildID {
+		for _, settings := range userGuildSettings {
+			if settings.GetGuildID() == guildID {
 				for _, override := range settings.ChannelOverrides {
-					if override.ChannelID == channel.ID {
-						if override.Muted {
+					if override.ChannelID == channelID {
+						if override.Muted && !isMuteExpired(channelID) {
 							return true
 						}
 
@@ -201,7 +286,9 @@ func HasBeenRead(channel *discordgo.Channel, lastMessageID string) bool {
 		return true
 	}
 
+	readStateMutex.RLock()
 	data, present := data[channel.ID]
+	readStateMutex.RUnlock()
 	if !present {
 		return false
 	}
@@ -0,0 +1,95 @@
+package readstate
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func TestNeedsAck(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.Ready = discordgo.Ready{
+		User: &discordgo.User{ID: "U1"},
+		ReadState: []*discordgo.ReadState{
+			{ID: "C1", LastMessageID: "1"},
+			{ID: "C2", LastMessageID: "2"},
+			{ID: "C3", LastMessageID: "1"},
+			{ID: "C4", LastMessageID: "1", MentionCount: 1},
+		},
+		UserGuildSettings: []*discordgo.UserGuildSettings{
+			{
+				GuildID: "G1",
+				ChannelOverrides: []*discordgo.UserGuildSettingsChannelOverride{
+					{ChannelID: "C3", Muted: true},
+					{ChannelID: "C4", Muted: true},
+				},
+			},
+		},
+	}
+	Load(sessionState)
+
+	tests := []struct {
+		name    string
+		channel *discordgo.Channel
+		want    bool
+	}{
+		{"unread", &discordgo.Channel{ID: "C1", GuildID: "G1", LastMessageID: "2"}, true},
+		{"read", &discordgo.Channel{ID: "C2", GuildID: "G1", LastMessageID: "2"}, false},
+		{"no messages", &discordgo.Channel{ID: "C5", GuildID: "G1"}, false},
+		{"muted", &discordgo.Channel{ID: "C3", GuildID: "G1", LastMessageID: "2"}, false},
+		{"muted with mentions", &discordgo.Channel{ID: "C4", GuildID: "G1", LastMessageID: "2"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsAck(tt.channel); got != tt.want {
+				t.Errorf("NeedsAck() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// staticTransport answers every request with the given status code.
+type staticTransport int
+
+func (statusCode staticTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: int(statusCode),
+		Status:     http.StatusText(int(statusCode)),
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    request,
+	}, nil
+}
+
+func TestAcknowledge(t *testing.T) {
+	sessionState := discordgo.NewState()
+	sessionState.Ready = discordgo.Ready{
+		User: &discordgo.User{ID: "U1"},
+		ReadState: []*discordgo.ReadState{
+			{ID: "C1", LastMessageID: "1", MentionCount: 1},
+		},
+	}
+	Load(sessionState)
+	channel := &discordgo.Channel{ID: "C1", LastMessageID: "2"}
+
+	session, _ := discordgo.NewWithToken("", "")
+	session.MaxRestRetries = 0
+	session.Client = &http.Client{Transport: staticTransport(http.StatusForbidden)}
+	if ackError := Acknowledge(session, channel, "2"); ackError == nil {
+		t.Error("Expected the acknowledgement to fail")
+	}
+	if GetLastReadMessageID("C1") != "1" || GetMentionCount("C1") != 1 {
+		t.Error("A failed acknowledgement mustn't change the read state")
+	}
+
+	session.Client = &http.Client{Transport: staticTransport(http.StatusOK)}
+	if ackError := Acknowledge(session, channel, "2"); ackError != nil {
+		t.Fatalf("Unexpected error: %s", ackError)
+	}
+	if GetLastReadMessageID("C1") != "2" || GetMentionCount("C1") != 0 {
+		t.Error("The read state should have been updated")
+	}
+}
@@ -16,6 +16,7 @@ var (
 	globalScope        = addScope("global", "Application wide", nil)
 	multilineTextInput = addScope("multiline_text_input", "Multiline text input", globalScope)
 	chatview           = addScope("chatview", "Chatview", globalScope)
+	treeView           = addScope("tree_view", "Guild, channel and private chat lists", globalScope)
 
 	QuoteSelectedMessage = addShortcut("quote_selected_message", "Quote selected message",
 		chatview, tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone))
@@ -29,9 +30,28 @@ var (
 		chatview, tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone))
 	ToggleSelectedMessageSpoilers = addShortcut("toggle_selected_message_spoilers", "Toggle spoilers in selected message",
 		chatview, tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone))
+	LoadOlderMessages = addShortcut("load_older_messages", "Load older messages",
+		chatview, tcell.NewEventKey(tcell.KeyRune, 'o', tcell.ModNone))
+	JumpToUnreadMarker = addShortcut("jump_to_unread_marker", "Jump to first unread message",
+		chatview, tcell.NewEventKey(tcell.KeyRune, 'u', tcell.ModNone))
+	StartFind = addShortcut("start_find", "Find text in messages",
+		chatview, tcell.NewEventKey(tcell.KeyRune, '/', tcell.ModNone))
+	FindNext = addShortcut("find_next", "Jump to next match",
+		chatview, tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone))
+	FindPrevious = addShortcut("find_previous", "Jump to previous match",
+		chatview, tcell.NewEventKey(tcell.KeyRune, 'N', tcell.ModNone))
+	ShowLinks = addShortcut("show_links", "Show links in selected or visible messages",
+		chatview, tcell.NewEventKey(tcell.KeyRune, 'L', tcell.ModNone))
+	ShowEditHistory = addShortcut("show_edit_history", "Show edit history of selected message",
+		chatview, tcell.NewEventKey(tcell.KeyRune, 'h', tcell.ModNone))
 	DeleteSelectedMessage = addShortcut("toggle_selected_message_spoilers", "Toggle spoilers in selected message",
 		chatview, tcell.NewEventKey(tcell.KeyDelete, 0, tcell.ModNone))
 
+	ToggleMute = addShortcut("toggle_mute", "Mute or unmute selected entry",
+		treeView, tcell.NewEventKey(tcell.KeyRune, 'M', tcell.ModAlt))
+	MarkGuildAsRead = addShortcut("mark_guild_as_read", "Mark selected guild or folder as read",
+		treeView, tcell.NewEventKey(tcell.KeyRune, 'R', tcell.ModAlt))
+
 	ExpandSelectionToLeft = addShortcut("expand_selection_word_to_left", "Expand selection word to left",
 		multilineTextInput, tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModShift))
 	ExpandSelectionToRight = addShortcut("expand_selection_word_to_right", "Expand selection word to right",
@@ -84,6 +104,32 @@ var (
 		globalScope, tcell.NewEventKey(tcell.KeyRune, 'p', tcell.ModAlt))
 	SwitchToPreviousChannel = addShortcut("switch_to_previous_channel", "Switch to previous channel",
 		globalScope, tcell.NewEventKey(tcell.KeyRune, 'l', tcell.ModAlt))
+	OpenNewTab = addShortcut("open_new_tab", "Open new tab",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'T', tcell.ModAlt))
+	CloseTab = addShortcut("close_tab", "Close current tab",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModAlt))
+	SwitchToNextTab = addShortcut("switch_to_next_tab", "Switch to next tab",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModAlt))
+	SwitchToPreviousTab = addShortcut("switch_to_previous_tab", "Switch to previous tab",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModAlt))
+	MoveTabRight = addShortcut("move_tab_right", "Move current tab to the right",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'N', tcell.ModAlt))
+	MoveTabLeft = addShortcut("move_tab_left", "Move current tab to the left",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'B', tcell.ModAlt))
+	ToggleSplitView = addShortcut("toggle_split_view", "Split chat into two panes",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'v', tcell.ModAlt))
+	SwitchChatPane = addShortcut("switch_chat_pane", "Switch between chat panes",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'o', tcell.ModAlt))
+	ToggleSplitDirection = addShortcut("toggle_split_direction", "Toggle direction of chat panes",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'V', tcell.ModAlt))
+	ShowMentionsInbox = addShortcut("show_mentions_inbox", "Show mentions inbox",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'i', tcell.ModAlt))
+	ShowQuickSwitcher = addShortcut("show_quick_switcher", "Show quick switcher",
+		globalScope, tcell.NewEventKey(tcell.KeyCtrlK, rune(tcell.KeyCtrlK), tcell.ModCtrl))
+	JumpToNextUnread = addShortcut("jump_to_next_unread", "Jump to next unread channel",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModAlt))
+	JumpToNextMention = addShortcut("jump_to_next_mention", "Jump to next mention",
+		globalScope, tcell.NewEventKey(tcell.KeyRune, 'J', tcell.ModAlt))
 	FocusMessageInput = addShortcut("focus_message_input", "Focus message input",
 		globalScope, tcell.NewEventKey(tcell.KeyRune, 'm', tcell.ModAlt))
 	FocusMessageContainer = addShortcut("focus_message_container", "Focus message container",
@@ -90,6 +90,27 @@ func (c *indexedTTYFormatter) Format(w io.Writer, style *chroma.Style, it chroma
 	return nil
 }
 
+// trueColorFormatter uses the exact colours of the style instead of mapping
+// them to a limited palette. This requires a terminal with truecolor support.
+type trueColorFormatter struct{}
+
+func (c *trueColorFormatter) Format(w io.Writer, style *chroma.Style, it chroma.Iterator) (err error) {
+	defer func() {
+		if perr := recover(); perr != nil {
+			err = perr.(error)
+		}
+	}()
+	for token := it(); token != chroma.EOF; token = it() {
+		entry := style.Get(token.Type)
+		if entry.Colour.IsSet() {
+			fmt.Fprintf(w, "[%s]", entry.Colour.String())
+		}
+		fmt.Fprint(w, token.Value)
+	}
+	return nil
+}
+
 func init() {
 	formatters.Register("tview-8bit", &indexedTTYFormatter{ttyTables[8]})
+	formatters.Register("tview-truecolor", &trueColorFormatter{})
 }
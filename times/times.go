@@ -2,20 +2,40 @@ package times
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Bios-Marcel/cordless/config"
 )
 
+const (
+	// Private use characters, that temporarily replace the weekday and month
+	// in layouts, so that those can be localised after formatting.
+	longWeekdayPlaceholder  = "\uE000"
+	shortWeekdayPlaceholder = "\uE001"
+	longMonthPlaceholder    = "\uE002"
+	shortMonthPlaceholder   = "\uE003"
+)
+
+var (
+	locationMutex        = &sync.Mutex{}
+	cachedLocation       *time.Location
+	cachedLocationConfig string
+)
+
 // TimeToLocalString formats a time to a string depending on the users settings.
-// The time will first be converted into a local time.
+// The time will first be converted into the configured timezone.
 func TimeToLocalString(time *time.Time) string {
-	localTime := time.Local()
+	localTime := ToConfiguredTimezone(*time)
 	return TimeToString(&localTime)
 }
 
 // TimeToString formats a time to a string depending on the users settings.
 func TimeToString(time *time.Time) string {
+	if config.GetConfig().TimeFormat != "" {
+		return FormatLocalised(*time, config.GetConfig().TimeFormat)
+	}
 	if config.GetConfig().Times == config.NoTime {
 		return ""
 	}
@@ -27,6 +47,85 @@ func TimeToString(time *time.Time) string {
 	return ""
 }
 
+// TimeToRelativeString formats the duration between the given time and now
+// in a short form, for example "5m ago". If the time lies more than a week in
+// the past, the result of TimeToLocalString is returned instead.
+func TimeToRelativeString(then, now time.Time) string {
+	difference := now.Sub(then)
+	if difference < time.Minute {
+		return "now"
+	}
+	if difference < time.Hour {
+		return fmt.Sprintf("%dm ago", int(difference/time.Minute))
+	}
+	if difference < 24*time.Hour {
+		return fmt.Sprintf("%dh ago", int(difference/time.Hour))
+	}
+	if difference < 7*24*time.Hour {
+		return fmt.Sprintf("%dd ago", int(difference/(24*time.Hour)))
+	}
+
+	return TimeToLocalString(&then)
+}
+
+// DateToLocalString formats the date of the given time depending on the users
+// settings. The time will first be converted into the configured timezone.
+func DateToLocalString(time time.Time) string {
+	layout := config.GetConfig().DateDelimiterFormat
+	if layout == "" {
+		layout = config.DefaultDateDelimiterFormat
+	}
+
+	return FormatLocalised(ToConfiguredTimezone(time), layout)
+}
+
+// FormatLocalised formats the time using the given go layout, but uses the
+// configured locale for the names of weekdays and months.
+func FormatLocalised(time time.Time, layout string) string {
+	names, localeExists := locales[config.GetConfig().DateLocale]
+	if !localeExists {
+		return time.Format(layout)
+	}
+
+	layout = strings.NewReplacer(
+		"Monday", longWeekdayPlaceholder,
+		"Mon", shortWeekdayPlaceholder,
+		"January", longMonthPlaceholder,
+		"Jan", shortMonthPlaceholder,
+	).Replace(layout)
+
+	return strings.NewReplacer(
+		longWeekdayPlaceholder, names.weekdays[time.Weekday()],
+		shortWeekdayPlaceholder, names.shortWeekdays[time.Weekday()],
+		longMonthPlaceholder, names.months[time.Month()-1],
+		shortMonthPlaceholder, names.shortMonths[time.Month()-1],
+	).Replace(time.Format(layout))
+}
+
+// ToConfiguredTimezone converts the time into the timezone set in the
+// configuration. If no timezone is set or the timezone doesn't exist, the
+// systems timezone is used.
+func ToConfiguredTimezone(time time.Time) time.Time {
+	return time.In(getConfiguredLocation())
+}
+
+func getConfiguredLocation() *time.Location {
+	locationMutex.Lock()
+	defer locationMutex.Unlock()
+
+	timezone := config.GetConfig().Timezone
+	if cachedLocation == nil || cachedLocationConfig != timezone {
+		location, loadError := time.LoadLocation(timezone)
+		if loadError != nil || timezone == "" {
+			location = time.Local
+		}
+		cachedLocation = location
+		cachedLocationConfig = timezone
+	}
+
+	return cachedLocation
+}
+
 // AreDatesTheSameDay returns true if the passed times represent the same day
 // of the year.
 func AreDatesTheSameDay(t1, t2 time.Time) bool {
@@ -3,6 +3,8 @@ package times
 import (
 	"testing"
 	"time"
+
+	"github.com/Bios-Marcel/cordless/config"
 )
 
 func TestAreDatesTheSameDay(t *testing.T) {
@@ -81,3 +83,97 @@ func TestAreDatesTheSameDay(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeToRelativeString(t *testing.T) {
+	now := time.Date(2000, 10, 10, 10, 10, 10, 0, time.UTC)
+	tests := []struct {
+		name string
+		then time.Time
+		want string
+	}{
+		{
+			name: "seconds ago",
+			then: now.Add(-30 * time.Second),
+			want: "now",
+		}, {
+			name: "minutes ago",
+			then: now.Add(-5 * time.Minute),
+			want: "5m ago",
+		}, {
+			name: "hours ago",
+			then: now.Add(-3*time.Hour - 59*time.Minute),
+			want: "3h ago",
+		}, {
+			name: "days ago",
+			then: now.Add(-50 * time.Hour),
+			want: "2d ago",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimeToRelativeString(tt.then, now); got != tt.want {
+				t.Errorf("TimeToRelativeString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLocalised(t *testing.T) {
+	oldLocale := config.GetConfig().DateLocale
+	defer func() { config.GetConfig().DateLocale = oldLocale }()
+
+	date := time.Date(2019, 3, 4, 10, 10, 10, 0, time.UTC)
+	tests := []struct {
+		name   string
+		locale string
+		layout string
+		want   string
+	}{
+		{
+			name:   "default locale",
+			locale: "",
+			layout: "Monday, 2. January 2006",
+			want:   "Monday, 4. March 2019",
+		}, {
+			name:   "unknown locale",
+			locale: "xx",
+			layout: "Mon Jan 2",
+			want:   "Mon Mar 4",
+		}, {
+			name:   "german",
+			locale: "de",
+			layout: "Monday, 2. January 2006",
+			want:   "Montag, 4. März 2019",
+		}, {
+			name:   "german short names",
+			locale: "de",
+			layout: "Mon, 02 Jan",
+			want:   "Mo, 04 Mär",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GetConfig().DateLocale = tt.locale
+			if got := FormatLocalised(date, tt.layout); got != tt.want {
+				t.Errorf("FormatLocalised() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToConfiguredTimezone(t *testing.T) {
+	oldTimezone := config.GetConfig().Timezone
+	defer func() { config.GetConfig().Timezone = oldTimezone }()
+
+	date := time.Date(2019, 3, 4, 23, 0, 0, 0, time.UTC)
+
+	config.GetConfig().Timezone = "Asia/Tokyo"
+	if hour := ToConfiguredTimezone(date).Hour(); hour != 8 {
+		t.Errorf("ToConfiguredTimezone().Hour() = %d, want 8", hour)
+	}
+
+	config.GetConfig().Timezone = "Invalid/Timezone"
+	if location := ToConfiguredTimezone(date).Location(); location != time.Local {
+		t.Errorf("ToConfiguredTimezone().Location() = %v, want %v", location, time.Local)
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 
@@ -109,6 +110,10 @@ func (channelTree *ChannelTree) LoadGuild(guildID string) error {
 		}
 		createSecondLevelChannelNodes(channelTree, channel)
 	}
+	channelTree.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		channelTree.updateNodeText(node)
+		return true
+	})
 	channelTree.SetCurrentNode(channelTree.GetRoot())
 	return nil
 }
@@ -182,7 +187,7 @@ func (channelTree *ChannelTree) AddOrUpdateChannel(channel *discordgo.Channel) {
 			}*/
 
 			updated = true
-			node.SetText(channel.Name)
+			channelTree.updateNodeText(node)
 
 			return false
 		}
@@ -258,6 +263,7 @@ func (channelTree *ChannelTree) MarkChannelAsUnread(channelID string) {
 		if ok && referenceChannelID == channelID {
 			channelTree.channelStates[node] = channelUnread
 			node.SetColor(config.GetTheme().AttentionColor)
+			channelTree.updateNodeAndParentText(node, parent)
 
 			return false
 		}
@@ -271,10 +277,7 @@ func (channelTree *ChannelTree) MarkChannelAsRead(channelID string) {
 	channelTree.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
 		referenceChannelID, ok := node.GetReference().(string)
 		if ok && referenceChannelID == channelID {
-			channel, stateError := channelTree.state.Channel(channelID)
-			if stateError == nil {
-				node.SetText(channel.Name)
-			}
+			channelTree.updateNodeAndParentText(node, parent)
 
 			if channelTree.channelStates[node] != channelLoaded {
 				channelTree.channelStates[node] = channelRead
@@ -294,10 +297,7 @@ func (channelTree *ChannelTree) MarkChannelAsMentioned(channelID string) {
 		referenceChannelID, ok := node.GetReference().(string)
 		if ok && referenceChannelID == channelID {
 			channelTree.channelStates[node] = channelMentioned
-			channel, stateError := channelTree.state.Channel(channelID)
-			if stateError == nil {
-				node.SetText("(@You) " + channel.Name)
-			}
+			channelTree.updateNodeAndParentText(node, parent)
 			node.SetColor(config.GetTheme().AttentionColor)
 
 			return false
@@ -322,10 +322,7 @@ func (channelTree *ChannelTree) MarkChannelAsLoaded(channelID string) {
 		referenceChannelID, ok := node.GetReference().(string)
 		if ok && referenceChannelID == channelID {
 			channelTree.channelStates[node] = channelLoaded
-			channel, stateError := channelTree.state.Channel(channelID)
-			if stateError == nil {
-				node.SetText(channel.Name)
-			}
+			channelTree.updateNodeAndParentText(node, parent)
 			node.SetColor(tview.Styles.ContrastBackgroundColor)
 			return false
 		}
@@ -334,6 +331,107 @@ func (channelTree *ChannelTree) MarkChannelAsLoaded(channelID string) {
 	})
 }
 
+// UpdateCounts updates the badges showing the amount of unread messages and
+// mentions of the given channel and of the category containing it.
+func (channelTree *ChannelTree) UpdateCounts(channelID string) {
+	channelTree.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		referenceChannelID, ok := node.GetReference().(string)
+		if ok && referenceChannelID == channelID {
+			channelTree.updateNodeAndParentText(node, parent)
+			return false
+		}
+
+		return true
+	})
+}
+
+func (channelTree *ChannelTree) updateNodeAndParentText(node, parent *tview.TreeNode) {
+	channelTree.updateNodeText(node)
+	if parent != nil && parent != channelTree.GetRoot() {
+		channelTree.updateNodeText(parent)
+	}
+}
+
+// updateNodeText sets the text of a channel node to the channel name followed
+// by the channels badge. The badge of a category sums up the badges of all
+// channels inside of the category.
+func (channelTree *ChannelTree) updateNodeText(node *tview.TreeNode) {
+	channelID, ok := node.GetReference().(string)
+	if !ok {
+		return
+	}
+	channel, stateError := channelTree.state.Channel(channelID)
+	if stateError != nil {
+		return
+	}
+
+	var unread, mentions int
+	if channel.Type == discordgo.ChannelTypeGuildCategory {
+		for _, childNode := range node.GetChildren() {
+			childID, ok := childNode.GetReference().(string)
+			if !ok {
+				continue
+			}
+			child, stateError := channelTree.state.Channel(childID)
+			if stateError != nil {
+				continue
+			}
+
+			childUnread, childMentions := readstate.GetChannelCounts(child)
+			unread += childUnread
+			mentions += childMentions
+		}
+	} else {
+		unread, mentions = readstate.GetChannelCounts(channel)
+	}
+
+	node.SetText(withMutedIndicator(channel.Name, readstate.IsChannelMuted(channel)) + formatCountBadge(unread, mentions))
+}
+
+// RefreshChannelStates updates the texts and colours of all channels, since
+// muting or unmuting channels changes whether they are considered read.
+func (channelTree *ChannelTree) RefreshChannelStates() {
+	channelTree.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		channelTree.updateNodeText(node)
+
+		channelID, ok := node.GetReference().(string)
+		if !ok {
+			return true
+		}
+		channel, stateError := channelTree.state.Channel(channelID)
+		if stateError != nil || channel.Type != discordgo.ChannelTypeGuildText {
+			return true
+		}
+		if state, tracked := channelTree.channelStates[node]; tracked && state == channelLoaded {
+			return true
+		}
+
+		if readstate.HasBeenRead(channel, channel.LastMessageID) {
+			channelTree.channelStates[node] = channelRead
+			node.SetColor(config.GetTheme().PrimaryTextColor)
+		} else if channelTree.channelStates[node] != channelMentioned {
+			channelTree.channelStates[node] = channelUnread
+			node.SetColor(config.GetTheme().AttentionColor)
+		}
+
+		return true
+	})
+}
+
+// formatCountBadge returns a badge showing the amount of unread messages and
+// mentions, for example " (3) @2". If both are zero, the badge is empty.
+func formatCountBadge(unread, mentions int) string {
+	var badge string
+	if unread > 0 {
+		badge += fmt.Sprintf(" (%d)", unread)
+	}
+	if mentions > 0 {
+		badge += fmt.Sprintf(" @%d", mentions)
+	}
+
+	return badge
+}
+
 // SetOnChannelSelect sets the handler that reacts to channel selection events.
 func (channelTree *ChannelTree) SetOnChannelSelect(handler func(channelID string)) {
 	channelTree.onChannelSelect = handler
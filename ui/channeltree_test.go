@@ -3,6 +3,7 @@ package ui
 import (
 	"testing"
 
+	"github.com/Bios-Marcel/cordless/readstate"
 	"github.com/Bios-Marcel/discordgo"
 	"github.com/gdamore/tcell"
 )
@@ -81,6 +82,8 @@ func TestChannelTree(t *testing.T) {
 		Roles:   []string{r1.ID},
 	})
 
+	readstate.Load(state)
+
 	tree := NewChannelTree(state)
 	loadError := tree.LoadGuild("G1")
 
@@ -109,3 +112,60 @@ func expectCell(expected rune, column, row int, screen tcell.SimulationScreen, t
 		t.Errorf("Cell missmatch. Was '%c' instead of '%c'.", cell, expected)
 	}
 }
+
+func Test_formatCountBadge(t *testing.T) {
+	tests := []struct {
+		unread, mentions int
+		want             string
+	}{
+		{0, 0, ""},
+		{3, 0, " (3)"},
+		{0, 2, " @2"},
+		{3, 2, " (3) @2"},
+	}
+	for _, tt := range tests {
+		if got := formatCountBadge(tt.unread, tt.mentions); got != tt.want {
+			t.Errorf("formatCountBadge(%d, %d) = %q, want %q", tt.unread, tt.mentions, got, tt.want)
+		}
+	}
+}
+
+func TestChannelTree_RefreshChannelStates(t *testing.T) {
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: "U1"}
+	stateError := state.GuildAdd(&discordgo.Guild{
+		ID:      "G1",
+		OwnerID: "U1",
+		Members: []*discordgo.Member{{GuildID: "G1", User: state.User}},
+		Channels: []*discordgo.Channel{
+			{ID: "C1", GuildID: "G1", Name: "general", Type: discordgo.ChannelTypeGuildText, LastMessageID: "2"},
+		},
+	})
+	if stateError != nil {
+		t.Fatalf("Error initializing state: %s", stateError)
+	}
+	readstate.Load(state)
+
+	tree := NewChannelTree(state)
+	if loadError := tree.LoadGuild("G1"); loadError != nil {
+		t.Fatalf("Error loading channeltree: %s", loadError)
+	}
+	node := tree.GetRoot().GetChildren()[0]
+	if tree.channelStates[node] != channelUnread {
+		t.Fatal("channel should be unread initially")
+	}
+
+	readstate.UpdateGuildSettings(&discordgo.UserGuildSettings{
+		GuildID: "G1",
+		ChannelOverrides: []*discordgo.UserGuildSettingsChannelOverride{
+			{ChannelID: "C1", Muted: true},
+		},
+	})
+	tree.RefreshChannelStates()
+	if tree.channelStates[node] != channelRead {
+		t.Error("muted channel should be considered read")
+	}
+	if text := node.GetText(); text != mutedIndicator+"general" {
+		t.Errorf("node text = %q, want the muted indicator", text)
+	}
+}
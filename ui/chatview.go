@@ -2,16 +2,21 @@ package ui
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	linkshortener "github.com/Bios-Marcel/shortnotforlong"
 
 	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/markdown"
+	"github.com/Bios-Marcel/cordless/maths"
+	"github.com/Bios-Marcel/cordless/shortcuts"
 	"github.com/Bios-Marcel/cordless/times"
 	"github.com/Bios-Marcel/cordless/ui/tviewutil"
 	"github.com/gdamore/tcell"
@@ -33,8 +38,19 @@ var (
 	colorRegex          = regexp.MustCompile("\\[#.{6}\\]")
 	channelMentionRegex = regexp.MustCompile(`<#\d*>`)
 	urlRegex            = regexp.MustCompile(`<?(https?://)(.+?)(/.+?)?($|\s|\||>)`)
-	spoilerRegex        = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
 	roleMentionRegex    = regexp.MustCompile(`<@&\d*>`)
+	placeholderRegex    = regexp.MustCompile(`\{([a-z]+)\}`)
+
+	// tagRegex matches the colour and region tags that tview supports.
+	tagRegex = regexp.MustCompile(`\[([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([a-zA-Z]+|#[0-9a-zA-Z]{6}|\-)?(:([lbdru]+|\-)?)?)?\]|\["([a-zA-Z0-9_,;: \-\.]*)"\]`)
+	// escapedTagRegex matches text that has been escaped via tview.Escape.
+	escapedTagRegex = regexp.MustCompile(`\[([a-zA-Z0-9_,;: \-\."#]+)\[(\[*)\]`)
+)
+
+const (
+	// defaultBufferSize is the amount of messages a ChatView holds, unless
+	// older messages have been explicitly loaded by the user.
+	defaultBufferSize = 100
 )
 
 // ChatView is using a tview.TextView in order to be able to display messages
@@ -49,7 +65,6 @@ type ChatView struct {
 	data       []*discordgo.Message
 	bufferSize int
 	ownUserID  string
-	format     string
 
 	shortenLinks bool
 
@@ -58,39 +73,104 @@ type ChatView struct {
 
 	showSpoilerContent map[string]bool
 	formattedMessages  map[string]string
-
-	onMessageAction func(message *discordgo.Message, event *tcell.EventKey) *tcell.EventKey
+	// deletedMessageIDs contains the IDs of all messages that have been
+	// deleted, but are kept in the view, depending on the configured
+	// DeletedMessageBehaviour.
+	deletedMessageIDs map[string]bool
+	// renderedMessages caches the text that each message in data has been
+	// written into the TextView with, including any delimiters in front of
+	// it. Changing a single message therefore only requires rendering that
+	// message again, while the TextView is still filled with all of them.
+	renderedMessages []string
+	// renderedWidth is the width of the TextView at the time of rendering
+	// the renderedMessages. Since delimiters depend on the width, all
+	// messages have to be rendered again if the width changes.
+	renderedWidth int
+	// formattingDetails remembers the circumstances under which each of the
+	// formattedMessages has been formatted.
+	formattingDetails map[string]formattingDetails
+
+	// loadingOlderMessages is true while a request for older messages is
+	// being processed, preventing duplicate requests.
+	loadingOlderMessages bool
+	// reachedChannelStart is true if there are no messages older than the
+	// oldest message that is currently being displayed.
+	reachedChannelStart bool
+	// oldestMessageID is the ID of the oldest message that has been passed
+	// to the view, including messages that aren't displayed, because they
+	// were sent by blocked users. Older messages are requested before it.
+	oldestMessageID string
+
+	// lastReadMessageID is the ID of the last message that had been read
+	// before the channel was loaded. All messages with a greater ID are
+	// preceded by an unread marker. A value of 0 means there is no marker.
+	lastReadMessageID uint64
+
+	// title is the title that is shown when the find mode isn't active.
+	title string
+	// findMode is true while the user is typing a search query.
+	findMode bool
+	// findQuery is the text that the user is searching for. The find mode
+	// is inactive if this is empty.
+	findQuery string
+	// findRegex is the compiled version of the findQuery.
+	findRegex *regexp.Regexp
+	// currentFindMatch is the index of the currently selected match out of
+	// all matches returned by findMatches.
+	currentFindMatch int
+	// currentFindMatchLocation is the location of the currently selected
+	// match, it is kept in order to avoid searching all messages on render.
+	currentFindMatchLocation findMatch
+
+	onMessageAction        func(message *discordgo.Message, event *tcell.EventKey) *tcell.EventKey
+	onOlderMessagesRequest func()
+	onLinksRequest         func(messages []*discordgo.Message)
 
 	mutex *sync.Mutex
 }
 
 // NewChatView constructs a new ready to use ChatView.
 func NewChatView(state *discordgo.State, ownUserID string) *ChatView {
+	chatView := newChatView(state, ownUserID)
+	if chatView.shortenLinks {
+		chatView.shortener = linkshortener.NewShortener(config.GetConfig().ShortenerPort)
+		go func() {
+			shortenerError := chatView.shortener.Start()
+			if shortenerError != nil {
+				//Disable shortening in case of start failure.
+				chatView.shortenLinks = false
+			}
+		}()
+	}
+
+	return chatView
+}
+
+// newSibling creates another ChatView for the same user. Instead of starting
+// another link shortener, the one of this ChatView is shared.
+func (chatView *ChatView) newSibling() *ChatView {
+	sibling := newChatView(chatView.state, chatView.ownUserID)
+	sibling.shortener = chatView.shortener
+	sibling.shortenLinks = chatView.shortenLinks
+	return sibling
+}
+
+func newChatView(state *discordgo.State, ownUserID string) *ChatView {
 	chatView := ChatView{
 		internalTextView:   tview.NewTextView(),
 		state:              state,
 		ownUserID:          ownUserID,
-		format:             "2006-01-02",
 		selection:          -1,
-		bufferSize:         100,
+		bufferSize:         defaultBufferSize,
 		selectionMode:      false,
 		showSpoilerContent: make(map[string]bool),
+		deletedMessageIDs:  make(map[string]bool),
 		shortenLinks:       config.GetConfig().ShortenLinks,
 		formattedMessages:  make(map[string]string),
+		formattingDetails:  make(map[string]formattingDetails),
 		mutex:              &sync.Mutex{},
 	}
 
-	if chatView.shortenLinks {
-		chatView.shortener = linkshortener.NewShortener(config.GetConfig().ShortenerPort)
-		go func() {
-			shortenerError := chatView.shortener.Start()
-			if shortenerError != nil {
-				//Disable shortening in case of start failure.
-				chatView.shortenLinks = false
-			}
-		}()
-	}
-
 	chatView.internalTextView.SetOnBlur(func() {
 		chatView.selectionMode = false
 		chatView.ClearSelection()
@@ -101,13 +181,58 @@ func NewChatView(state *discordgo.State, ownUserID string) *ChatView {
 	})
 
 	chatView.internalTextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if chatView.findMode {
+			return chatView.handleFindInput(event)
+		}
+
 		if chatView.selectionMode && event.Modifiers() == tcell.ModNone {
+			if shortcuts.StartFind.Equals(event) {
+				chatView.startFind()
+				return nil
+			}
+
+			if chatView.findQuery != "" {
+				if shortcuts.FindNext.Equals(event) {
+					chatView.jumpToFindMatch(chatView.currentFindMatch + 1)
+					return nil
+				}
+
+				if shortcuts.FindPrevious.Equals(event) {
+					chatView.jumpToFindMatch(chatView.currentFindMatch - 1)
+					return nil
+				}
+
+				if event.Key() == tcell.KeyEsc {
+					chatView.StopFind()
+					return nil
+				}
+			}
+
+			if shortcuts.LoadOlderMessages.Equals(event) {
+				chatView.requestOlderMessages()
+				return nil
+			}
+
+			if shortcuts.ShowLinks.Equals(event) {
+				if chatView.onLinksRequest != nil {
+					chatView.onLinksRequest(chatView.getLinkSourceMessages())
+				}
+				return nil
+			}
+
+			if shortcuts.JumpToUnreadMarker.Equals(event) {
+				chatView.JumpToUnreadMarker()
+				return nil
+			}
+
 			if event.Key() == tcell.KeyUp {
 				if chatView.selection == -1 {
 					chatView.selection = len(chatView.data) - 1
 				} else if chatView.selection >= 1 {
 					chatView.selection--
 				} else {
+					//Moving past the first message loads the previous page.
+					chatView.requestOlderMessages()
 					return nil
 				}
 
@@ -159,8 +284,8 @@ func NewChatView(state *discordgo.State, ownUserID string) *ChatView {
 				} else {
 					chatView.showSpoilerContent[messageID] = true
 				}
-				chatView.formattedMessages[messageID] = chatView.formatMessage(message)
-				chatView.Rerender()
+				delete(chatView.formattedMessages, messageID)
+				chatView.rerenderMessages(chatView.selection)
 				return nil
 			}
 
@@ -185,7 +310,10 @@ func NewChatView(state *discordgo.State, ownUserID string) *ChatView {
 
 // SetTitle sets the border text of the chatview.
 func (chatView *ChatView) SetTitle(text string) {
-	chatView.internalTextView.SetTitle(text)
+	chatView.title = text
+	if chatView.findQuery == "" && !chatView.findMode {
+		chatView.internalTextView.SetTitle(text)
+	}
 }
 
 // SetOnMessageAction sets the handler that will get called if the user tries
@@ -194,14 +322,40 @@ func (chatView *ChatView) SetOnMessageAction(onMessageAction func(message *disco
 	chatView.onMessageAction = onMessageAction
 }
 
-func intToString(value int) string {
-	return strconv.FormatInt(int64(value), 10)
+// SetOnOlderMessagesRequest sets the handler that will get called if the user
+// tries to scroll past the oldest message or explicitly asks for older
+// messages. The handler won't be called if the start of the channel has
+// already been reached or if a request is still in progress.
+func (chatView *ChatView) SetOnOlderMessagesRequest(handler func()) {
+	chatView.onOlderMessagesRequest = handler
+}
+
+// SetOnLinksRequest sets the handler that will get called if the user wants
+// to see the links of the selected message or, if no message is selected, of
+// all visible messages.
+func (chatView *ChatView) SetOnLinksRequest(handler func(messages []*discordgo.Message)) {
+	chatView.onLinksRequest = handler
+}
+
+func (chatView *ChatView) requestOlderMessages() {
+	if chatView.onOlderMessagesRequest != nil && chatView.oldestMessageID != "" &&
+		!chatView.loadingOlderMessages && !chatView.reachedChannelStart {
+		chatView.onOlderMessagesRequest()
+	}
+}
+
+// IsScrolledToStart checks whether the top of the first line is visible.
+func (chatView *ChatView) IsScrolledToStart() bool {
+	row, _ := chatView.internalTextView.GetScrollOffset()
+	return row <= 0
 }
 
 func (chatView *ChatView) updateHighlights() {
-	chatView.internalTextView.Highlight(intToString(chatView.selection))
-	if chatView.selection != -1 {
+	if chatView.selection >= 0 && chatView.selection < len(chatView.data) {
+		chatView.internalTextView.Highlight(chatView.data[chatView.selection].ID)
 		chatView.internalTextView.ScrollToHighlight()
+	} else {
+		chatView.internalTextView.Highlight()
 	}
 }
 
@@ -211,55 +365,116 @@ func (chatView *ChatView) GetPrimitive() tview.Primitive {
 	return chatView.internalTextView
 }
 
-// UpdateMessage reformats the passed message, updates the cache and triggers
-// a rerender.
+// UpdateMessage reformats the passed message, updates the cache and fills
+// the TextView again. All other messages are taken from the cache.
 func (chatView *ChatView) UpdateMessage(updatedMessage *discordgo.Message) {
-	for _, message := range chatView.data {
+	for index, message := range chatView.data {
 		if message.ID == updatedMessage.ID {
-			chatView.formattedMessages[updatedMessage.ID] = chatView.formatMessage(updatedMessage)
-			chatView.Rerender()
+			delete(chatView.formattedMessages, updatedMessage.ID)
+			chatView.rerenderMessages(index)
 			break
 		}
 	}
 }
 
-// DeleteMessage drops the message from the cache and triggers a rerender
+// DeleteMessage drops the message from the cache and removes it from the
+// view.
 func (chatView *ChatView) DeleteMessage(deletedMessage *discordgo.Message) {
-	delete(chatView.showSpoilerContent, deletedMessage.ID)
-	delete(chatView.formattedMessages, deletedMessage.ID)
-	filteredMessages := make([]*discordgo.Message, 0)
-	for _, message := range chatView.data {
-		if message.ID != deletedMessage.ID {
-			filteredMessages = append(filteredMessages, message)
-		}
-	}
-	chatView.data = filteredMessages
-	chatView.Rerender()
+	chatView.DeleteMessages([]string{deletedMessage.ID})
 }
 
-// DeleteMessages drops the messages from the cache and triggers a rerender
+// DeleteMessages drops the messages from the cache and removes them from the
+// view. Only the messages following the deleted ones are rendered again, the
+// others are taken from the cache.
+// Depending on the configured DeletedMessageBehaviour, the messages are only
+// marked as deleted instead. If the selected message is deleted, the
+// selection is cleared, so that no actions can be applied to it anymore.
 func (chatView *ChatView) DeleteMessages(deletedMessages []string) {
-	filteredMessages := make([]*discordgo.Message, 0)
+	if chatView.selection >= 0 && chatView.selection < len(chatView.data) {
+		selectedMessageID := chatView.data[chatView.selection].ID
+		for _, message := range deletedMessages {
+			if message == selectedMessageID {
+				chatView.selection = -1
+				chatView.internalTextView.Highlight()
+				break
+			}
+		}
+	}
 
+	if config.GetConfig().DeletedMessageBehaviour != config.RemoveDeletedMessages {
+		chatView.markMessagesAsDeleted(deletedMessages)
+		return
+	}
+
+	toDelete := make(map[string]bool, len(deletedMessages))
 	for _, message := range deletedMessages {
+		toDelete[message] = true
 		delete(chatView.showSpoilerContent, message)
 		delete(chatView.formattedMessages, message)
-
+		delete(chatView.formattingDetails, message)
 	}
 
-OUTER_LOOP:
-	for _, message := range chatView.data {
-		for _, toDelete := range deletedMessages {
-			if toDelete == message.ID {
-				continue OUTER_LOOP
-			}
+	filteredMessages := make([]*discordgo.Message, 0, len(chatView.data))
+	filteredRenderedMessages := make([]string, 0, len(chatView.data))
+	//The delimiters and the formatting of a message depend on the previous
+	//messages, therefore successors of deleted messages and the messages of
+	//the same author following them have to be rendered again.
+	var successorsOfDeleted []int
+	previousDeleted := false
+	newSelection := -1
+	for index, message := range chatView.data {
+		if toDelete[message.ID] {
+			previousDeleted = true
+			continue
 		}
 
+		if index == chatView.selection {
+			newSelection = len(filteredMessages)
+		}
+
+		if previousDeleted {
+			successorsOfDeleted = append(successorsOfDeleted, len(filteredMessages))
+			previousDeleted = false
+		} else if len(successorsOfDeleted) > 0 && successorsOfDeleted[len(successorsOfDeleted)-1] == len(filteredMessages)-1 &&
+			chatView.continuesSequence(filteredMessages[len(filteredMessages)-1], message) {
+			//The start of the group might have moved, which affects all
+			//following messages of the same author.
+			successorsOfDeleted = append(successorsOfDeleted, len(filteredMessages))
+		}
 		filteredMessages = append(filteredMessages, message)
+		filteredRenderedMessages = append(filteredRenderedMessages, chatView.renderedMessages[index])
+	}
+
+	if len(filteredMessages) == len(chatView.data) {
+		return
 	}
 
 	chatView.data = filteredMessages
-	chatView.Rerender()
+	chatView.renderedMessages = filteredRenderedMessages
+	chatView.selection = newSelection
+	chatView.rerenderMessages(successorsOfDeleted...)
+}
+
+// markMessagesAsDeleted renders the given messages again, marking them as
+// deleted. Messages that aren't part of the view are ignored.
+func (chatView *ChatView) markMessagesAsDeleted(deletedMessages []string) {
+	toMark := make(map[string]bool, len(deletedMessages))
+	for _, message := range deletedMessages {
+		toMark[message] = true
+	}
+
+	var indicesToRender []int
+	for index, message := range chatView.data {
+		if toMark[message.ID] && !chatView.deletedMessageIDs[message.ID] {
+			chatView.deletedMessageIDs[message.ID] = true
+			delete(chatView.formattedMessages, message.ID)
+			indicesToRender = append(indicesToRender, index)
+		}
+	}
+
+	if len(indicesToRender) > 0 {
+		chatView.rerenderMessages(indicesToRender...)
+	}
 }
 
 // ClearViewAndCache clears the TextView buffer and removes all data for
@@ -267,63 +482,263 @@ OUTER_LOOP:
 func (chatView *ChatView) ClearViewAndCache() {
 	chatView.data = make([]*discordgo.Message, 0)
 	chatView.showSpoilerContent = make(map[string]bool)
+	chatView.deletedMessageIDs = make(map[string]bool)
 	chatView.formattedMessages = make(map[string]string)
+	chatView.formattingDetails = make(map[string]formattingDetails)
+	chatView.renderedMessages = nil
 	chatView.selection = -1
+	chatView.bufferSize = defaultBufferSize
+	chatView.loadingOlderMessages = false
+	chatView.reachedChannelStart = false
+	chatView.oldestMessageID = ""
+	chatView.lastReadMessageID = 0
+	chatView.findMode = false
+	chatView.findQuery = ""
+	chatView.findRegex = nil
 	chatView.internalTextView.SetText("")
 	chatView.SetTitle("")
 }
 
 func (chatView *ChatView) addMessageInternal(message *discordgo.Message) {
+	if chatView.oldestMessageID == "" {
+		chatView.oldestMessageID = message.ID
+	}
+
 	isBlocked := discordutil.IsBlocked(chatView.state, message.Author)
 
 	if !config.GetConfig().ShowPlaceholderForBlockedMessages && isBlocked {
 		return
 	}
 
-	var rerender bool
 	if len(chatView.data) >= chatView.bufferSize {
 		idToDrop := chatView.data[0].ID
 		delete(chatView.showSpoilerContent, idToDrop)
+		delete(chatView.deletedMessageIDs, idToDrop)
 		delete(chatView.formattedMessages, idToDrop)
+		delete(chatView.formattingDetails, idToDrop)
 		chatView.data = append(chatView.data[1:], message)
-		rerender = true
+		chatView.renderedMessages = append(chatView.renderedMessages[1:], "")
+		chatView.oldestMessageID = chatView.data[0].ID
 		if chatView.selection > -1 {
 			chatView.selection--
 		}
+
+		//The new first message needs a date delimiter in front of it. Since
+		//the TextView can't remove text, this writes all messages again.
+		chatView.rerenderMessages(0, len(chatView.data)-1)
 		chatView.updateHighlights()
+		return
+	}
+
+	chatView.data = append(chatView.data, message)
+	messageIndex := len(chatView.data) - 1
+	renderedMessage := chatView.renderMessage(messageIndex, chatView.getFollowUpType(chatView.data, messageIndex))
+	chatView.renderedMessages = append(chatView.renderedMessages, renderedMessage)
+	if chatView.renderedWidth != chatView.getWidth() {
+		chatView.Rerender()
 	} else {
-		chatView.data = append(chatView.data, message)
+		fmt.Fprint(chatView.internalTextView, renderedMessage)
+	}
+}
+
+// formattingDetails are the circumstances that a formatted message depends
+// on, apart from the message itself.
+type formattingDetails struct {
+	followUp followUpType
+	timeText string
+}
+
+// rendersLike checks whether a message formatted with the given details
+// looks the same as one formatted with these details. Follow-ups don't
+// display their time, therefore only the width of the time text matters.
+func (details formattingDetails) rendersLike(other formattingDetails) bool {
+	if details.followUp != other.followUp {
+		return false
+	}
+
+	if details.followUp == noFollowUp {
+		return details.timeText == other.timeText
 	}
 
-	var newText string
+	return tview.TaggedStringWidth(tview.Escape(details.timeText)) == tview.TaggedStringWidth(tview.Escape(other.timeText))
+}
+
+// getOrFormatMessage returns the cached text for the message or formats and
+// caches it, in case it hasn't been formatted yet. Since the formatting
+// depends on the previous messages and the current time, the cache is
+// ignored if any of those led to a different result.
+func (chatView *ChatView) getOrFormatMessage(message *discordgo.Message, followUp followUpType) string {
+	details := formattingDetails{
+		followUp: followUp,
+		timeText: formatMessageTime(message.Timestamp),
+	}
 	formattedMessage, messageAlreadyFormatted := chatView.formattedMessages[message.ID]
-	if messageAlreadyFormatted {
-		newText = formattedMessage
+	if messageAlreadyFormatted && chatView.formattingDetails[message.ID].rendersLike(details) {
+		return formattedMessage
+	}
+
+	if discordutil.IsBlocked(chatView.state, message.Author) {
+		formattedMessage = chatView.applyMessageTemplate(details, map[string]string{
+			"author":   "[" + tviewutil.ColorToHex(config.GetTheme().BlockedUserColor) + "]" + alignToAuthorColumn("Blocked user"),
+			"nick":     alignToAuthorColumn("Blocked user"),
+			"username": alignToAuthorColumn("Blocked user"),
+			"content":  "Blocked message",
+		})
 	} else {
-		if isBlocked {
-			newText = chatView.messagePartsToColouredString(message.Timestamp, "Blocked user", "Blocked message")
-		} else {
-			newText = chatView.formatMessage(message)
+		formattedMessage = chatView.formatMessage(message, details)
+	}
+	chatView.formattedMessages[message.ID] = formattedMessage
+	chatView.formattingDetails[message.ID] = details
+
+	return formattedMessage
+}
+
+// formatMessageTime returns the time of a message as it should be displayed,
+// depending on whether relative times are enabled.
+func formatMessageTime(timestamp discordgo.Timestamp) string {
+	messageTime, parseError := timestamp.Parse()
+	if parseError != nil {
+		return ""
+	}
+
+	if config.GetConfig().RelativeTimes {
+		return times.TimeToRelativeString(messageTime, time.Now())
+	}
+
+	return times.TimeToLocalString(&messageTime)
+}
+
+// RefreshTimes reformats all messages whose displayed relative time has
+// changed since they were formatted. If no time has changed, nothing happens.
+func (chatView *ChatView) RefreshTimes() {
+	var outdatedMessages []int
+	for index, message := range chatView.data {
+		details, formatted := chatView.formattingDetails[message.ID]
+		if !formatted {
+			continue
+		}
+
+		refreshedDetails := formattingDetails{
+			followUp: details.followUp,
+			timeText: formatMessageTime(message.Timestamp),
+		}
+		if !details.rendersLike(refreshedDetails) {
+			outdatedMessages = append(outdatedMessages, index)
 		}
-		chatView.formattedMessages[message.ID] = newText
 	}
 
-	if rerender {
-		chatView.Rerender()
-	} else {
-		fmt.Fprint(chatView.internalTextView, "\n[\""+intToString(len(chatView.data)-1)+"\"]"+newText)
+	if len(outdatedMessages) > 0 {
+		chatView.rerenderMessages(outdatedMessages...)
+	}
+}
+
+// RefreshAuthor formats all messages of the given user again, for example
+// because the users roles have changed.
+func (chatView *ChatView) RefreshAuthor(userID string) {
+	var outdatedMessages []int
+	for index, message := range chatView.data {
+		if message.Author.ID == userID {
+			delete(chatView.formattedMessages, message.ID)
+			outdatedMessages = append(outdatedMessages, index)
+		}
+	}
+
+	if len(outdatedMessages) > 0 {
+		chatView.rerenderMessages(outdatedMessages...)
+	}
+}
+
+// RefreshAllMessages formats all messages again, for example because the
+// roles of the guild have changed.
+func (chatView *ChatView) RefreshAllMessages() {
+	chatView.formattedMessages = make(map[string]string)
+	chatView.Rerender()
+}
+
+// followUpType decides how much of the message template is omitted for a
+// message, because it follows a message of the same author.
+type followUpType int
+
+const (
+	// noFollowUp means that the message is rendered completely.
+	noFollowUp followUpType = iota
+	// compactFollowUp means that the time of the message is omitted.
+	compactFollowUp
+	// groupedFollowUp means that everything in front of the content is
+	// omitted, making the message part of the previous messages group.
+	groupedFollowUp
+)
+
+// getFollowUpType checks whether the message at the given index directly
+// follows a message of the same author on the same day. See
+// getFollowUpTypes for details. Only the sequence of messages of the same
+// author that the message belongs to is inspected.
+func (chatView *ChatView) getFollowUpType(messages []*discordgo.Message, index int) followUpType {
+	sequenceStart := index
+	for sequenceStart > 0 && chatView.continuesSequence(messages[sequenceStart-1], messages[sequenceStart]) {
+		sequenceStart--
+	}
+
+	return chatView.getFollowUpTypes(messages[sequenceStart : index+1])[index-sequenceStart]
+}
+
+// getFollowUpTypes decides for each of the messages whether it directly
+// follows a message of the same author on the same day. Messages sent within
+// the configured grouping window, measured from the first message of the
+// group, are grouped, otherwise the message is compacted, if compact messages
+// are enabled. The start of the current group is carried forward, so that
+// every message is only inspected once.
+func (chatView *ChatView) getFollowUpTypes(messages []*discordgo.Message) []followUpType {
+	followUps := make([]followUpType, len(messages))
+	groupingWindow := time.Duration(config.GetConfig().MessageGroupingWindow) * time.Second
+	if !config.GetConfig().CompactMessages && groupingWindow <= 0 {
+		return followUps
+	}
+
+	var groupStart time.Time
+	for index, message := range messages {
+		sent, _ := message.Timestamp.Parse()
+		if index == 0 || !chatView.continuesSequence(messages[index-1], message) {
+			groupStart = sent
+			continue
+		}
+
+		if groupingWindow > 0 && sent.Sub(groupStart) <= groupingWindow {
+			followUps[index] = groupedFollowUp
+			continue
+		}
+
+		// A new group starts with every message that lies outside of the
+		// window of the group it follows.
+		groupStart = sent
+		if config.GetConfig().CompactMessages {
+			followUps[index] = compactFollowUp
+		}
+	}
+
+	return followUps
+}
+
+// continuesSequence checks whether the message has been sent by the author of
+// the previous message on the same day, without the unread marker in between.
+func (chatView *ChatView) continuesSequence(previousMessage, message *discordgo.Message) bool {
+	if previousMessage.Author.ID != message.Author.ID ||
+		chatView.isFirstUnreadMessage(previousMessage, message) {
+		return false
 	}
+
+	t1, _ := previousMessage.Timestamp.Parse()
+	t2, _ := message.Timestamp.Parse()
+	return times.AreDatesTheSameDay(times.ToConfiguredTimezone(t1), times.ToConfiguredTimezone(t2))
 }
 
-//AddMessage add an additional message to the ChatView.
+// AddMessage add an additional message to the ChatView. Since messages are
+// only added while the channel is being shown, the channel is considered
+// read and the unread marker is removed.
 func (chatView *ChatView) AddMessage(message *discordgo.Message) {
 	wasScrolledToTheEnd := chatView.internalTextView.IsScrolledToEnd()
-	t1, _ := chatView.data[len(chatView.data)-1].Timestamp.Parse()
-	t2, _ := message.Timestamp.Parse()
-	if !times.AreDatesTheSameDay(t1.Local(), t2.Local()) {
-		fmt.Fprint(chatView.internalTextView, chatView.CreateDateDelimiter(t2.Format(chatView.format)))
-	}
 
+	chatView.ClearUnreadMarker()
 	chatView.addMessageInternal(message)
 
 	chatView.updateHighlights()
@@ -335,8 +750,9 @@ func (chatView *ChatView) AddMessage(message *discordgo.Message) {
 // CreateDateDelimiter creates a date delimiter between messages to mark the date and returns it
 func (chatView *ChatView) CreateDateDelimiter(date string) string {
 	_, _, width, _ := chatView.internalTextView.GetInnerRect()
-	dashes := (width - len(chatView.format)) / 2
-	padding := strings.Repeat("\u2500", dashes-1)
+	date = tview.Escape(date)
+	dashes := (width - tview.TaggedStringWidth(date)) / 2
+	padding := strings.Repeat("\u2500", maths.Max(0, dashes-1))
 	dateDelimiterLine := "\n[\"" + "\"]" + padding + " " + date + " " + padding
 	return dateDelimiterLine
 }
@@ -345,23 +761,96 @@ func (chatView *ChatView) CreateDateDelimiter(date string) string {
 func (chatView *ChatView) ReturnDateDelimiter(messages []*discordgo.Message, index int) string {
 	if index == 0 {
 		time, _ := messages[index].Timestamp.Parse()
-		date := time.Format(chatView.format)
-		return chatView.CreateDateDelimiter(date)
+		return chatView.CreateDateDelimiter(times.DateToLocalString(time))
 	}
 
 	t1, _ := messages[index-1].Timestamp.Parse()
 	t2, _ := messages[index].Timestamp.Parse()
 
-	if !times.AreDatesTheSameDay(t1.Local(), t2.Local()) {
-		return chatView.CreateDateDelimiter(t2.Local().Format(chatView.format))
+	if !times.AreDatesTheSameDay(times.ToConfiguredTimezone(t1), times.ToConfiguredTimezone(t2)) {
+		return chatView.CreateDateDelimiter(times.DateToLocalString(t2))
+	}
+
+	return ""
+}
+
+// CreateUnreadDelimiter creates the delimiter that marks the beginning of the
+// messages that haven't been read before loading the channel.
+func (chatView *ChatView) CreateUnreadDelimiter() string {
+	_, _, width, _ := chatView.internalTextView.GetInnerRect()
+	text := "new messages"
+	dashes := (width - len(text)) / 2
+	padding := strings.Repeat("\u2500", maths.Max(0, dashes-1))
+	return "\n[\"\"][" + tviewutil.ColorToHex(config.GetTheme().AttentionColor) + "]" +
+		padding + " " + text + " " + padding + "[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]"
+}
+
+// ReturnUnreadDelimiter returns an unread delimiter if the message at the
+// given index is the first unread message, otherwise an empty string.
+func (chatView *ChatView) ReturnUnreadDelimiter(messages []*discordgo.Message, index int) string {
+	var previousMessage *discordgo.Message
+	if index > 0 {
+		previousMessage = messages[index-1]
+	}
+
+	if chatView.isFirstUnreadMessage(previousMessage, messages[index]) {
+		return chatView.CreateUnreadDelimiter()
 	}
 
 	return ""
 }
 
-// WriteDateDelimiter runs ReturnDateDelimiter and writes it to chatView.internalTextView
-func (chatView *ChatView) WriteDateDelimiter(messages []*discordgo.Message, index int) {
-	fmt.Fprint(chatView.internalTextView, chatView.ReturnDateDelimiter(messages, index))
+// isFirstUnreadMessage checks whether the message is unread, while the
+// previous message, which may be nil, isn't.
+func (chatView *ChatView) isFirstUnreadMessage(previousMessage, message *discordgo.Message) bool {
+	if chatView.lastReadMessageID == 0 || !chatView.isUnread(message) {
+		return false
+	}
+
+	return previousMessage == nil || !chatView.isUnread(previousMessage)
+}
+
+func (chatView *ChatView) isUnread(message *discordgo.Message) bool {
+	parsed, parseError := strconv.ParseUint(message.ID, 10, 64)
+	return parseError == nil && parsed > chatView.lastReadMessageID
+}
+
+// SetUnreadMarker defines the last message that has been read. All messages
+// after this message will be preceded by an unread marker. Passing an empty
+// string is the same as calling ClearUnreadMarker. This doesn't trigger a
+// rerender, therefore it should be called before setting the messages.
+func (chatView *ChatView) SetUnreadMarker(lastReadMessageID string) {
+	parsed, parseError := strconv.ParseUint(lastReadMessageID, 10, 64)
+	if parseError != nil {
+		chatView.lastReadMessageID = 0
+	} else {
+		chatView.lastReadMessageID = parsed
+	}
+}
+
+// ClearUnreadMarker removes the unread marker and triggers a rerender if
+// a marker was present.
+func (chatView *ChatView) ClearUnreadMarker() {
+	if chatView.lastReadMessageID != 0 {
+		chatView.lastReadMessageID = 0
+		chatView.Rerender()
+	}
+}
+
+// JumpToUnreadMarker selects the first unread message. If there is no
+// unread marker, nothing happens.
+func (chatView *ChatView) JumpToUnreadMarker() {
+	if chatView.lastReadMessageID == 0 {
+		return
+	}
+
+	for index, message := range chatView.data {
+		if chatView.isUnread(message) {
+			chatView.selection = index
+			chatView.updateHighlights()
+			return
+		}
+	}
 }
 
 // AddMessages is the same as AddMessage, but for an array of messages instead
@@ -371,8 +860,7 @@ func (chatView *ChatView) WriteDateDelimiter(messages []*discordgo.Message, inde
 func (chatView *ChatView) AddMessages(messages []*discordgo.Message) {
 	wasScrolledToTheEnd := chatView.internalTextView.IsScrolledToEnd()
 
-	for index, message := range messages {
-		chatView.WriteDateDelimiter(messages, index)
+	for _, message := range messages {
 		chatView.addMessageInternal(message)
 	}
 
@@ -382,84 +870,889 @@ func (chatView *ChatView) AddMessages(messages []*discordgo.Message) {
 	}
 }
 
-// Rerender clears the text view and fills it again using the current cache.
-func (chatView *ChatView) Rerender() {
-	chatView.internalTextView.SetText("")
-	var newContent string
-	for index, message := range chatView.data {
-		formattedMessage, contains := chatView.formattedMessages[message.ID]
-		//Should always be true, otherwise we got ourselves a bug.
-		if contains {
-			newContent += chatView.ReturnDateDelimiter(chatView.data, index)
-			newContent = newContent + "\n[\"" + intToString(index) + "\"]" + formattedMessage
-		} else {
-			panic("Bug in chatview, a message could not be found.")
-		}
+// PrependMessages adds messages that are older than the currently displayed
+// messages in front of them. The messages are expected to be sorted by their
+// timestamps. Neither the current selection nor the scroll position will
+// visually change, since both are moved by the amount of added content.
+func (chatView *ChatView) PrependMessages(messages []*discordgo.Message) {
+	if len(messages) > 0 {
+		chatView.oldestMessageID = messages[0].ID
 	}
-	fmt.Fprint(chatView.internalTextView, newContent)
-}
-
-func (chatView *ChatView) formatMessage(message *discordgo.Message) string {
-	return chatView.messagePartsToColouredString(
-		message.Timestamp,
-		chatView.formatMessageAuthor(message),
-		chatView.formatMessageText(message))
-}
 
-func (chatView *ChatView) formatMessageAuthor(message *discordgo.Message) string {
-	var messageAuthor string
-	if message.GuildID != "" {
-		member, cacheError := chatView.state.Member(message.GuildID, message.Author.ID)
-		if cacheError == nil {
-			messageAuthor = discordutil.GetMemberName(member)
+	olderMessages := make([]*discordgo.Message, 0, len(messages))
+	for _, message := range messages {
+		if !config.GetConfig().ShowPlaceholderForBlockedMessages &&
+			discordutil.IsBlocked(chatView.state, message.Author) {
+			continue
 		}
+
+		olderMessages = append(olderMessages, message)
 	}
 
-	if messageAuthor == "" {
-		messageAuthor = discordutil.GetUserName(message.Author)
+	if len(olderMessages) == 0 {
+		return
 	}
 
-	if config.GetConfig().UseRandomUserColors {
-		return "[" + discordutil.GetUserColor(message.Author) + "]" + messageAuthor
+	_, _, width, _ := chatView.internalTextView.GetInnerRect()
+	oldHeight := chatView.calculateContentHeight(width)
+	scrollRow, _ := chatView.internalTextView.GetScrollOffset()
+
+	chatView.data = append(olderMessages, chatView.data...)
+	chatView.renderedMessages = append(make([]string, len(olderMessages)), chatView.renderedMessages...)
+	chatView.bufferSize = maths.Max(chatView.bufferSize, len(chatView.data))
+	//The previously first message might not need a date delimiter anymore.
+	indicesToRender := make([]int, 0, len(olderMessages)+1)
+	for index := 0; index <= len(olderMessages); index++ {
+		indicesToRender = append(indicesToRender, index)
 	}
+	chatView.rerenderMessages(indicesToRender...)
 
-	return "[" + tviewutil.ColorToHex(config.GetTheme().DefaultUserColor) + "]" + messageAuthor
+	if chatView.selection != -1 {
+		chatView.selection += len(olderMessages)
+		chatView.updateHighlights()
+	} else {
+		addedHeight := chatView.calculateContentHeight(width) - oldHeight
+		chatView.internalTextView.ScrollTo(maths.Max(0, scrollRow)+addedHeight, 0)
+	}
 }
 
-func (chatView *ChatView) formatMessageText(message *discordgo.Message) string {
-	if message.Type == discordgo.MessageTypeDefault {
-		return chatView.formatDefaultMessageText(message)
-	} else if message.Type == discordgo.MessageTypeGuildMemberJoin {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]joined the server."
-	} else if message.Type == discordgo.MessageTypeCall {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]has started a call."
-	} else if message.Type == discordgo.MessageTypeChannelIconChange {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]changed the channel icon."
-	} else if message.Type == discordgo.MessageTypeChannelNameChange {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]changed the channel name to " + message.Content + "."
-	} else if message.Type == discordgo.MessageTypeChannelPinnedMessage {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]pinned a message."
-	} else if message.Type == discordgo.MessageTypeRecipientAdd {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]added " + message.Mentions[0].Username + " to the group."
-	} else if message.Type == discordgo.MessageTypeRecipientRemove {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]removed " + message.Mentions[0].Username + " from the group."
-	} else if message.Type == discordgo.MessageTypeChannelFollowAdd {
-		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]has added '" + message.Content + "' to this channel"
+// getLinkSourceMessages returns the selected message or, if there's no
+// selection, all messages that are currently visible.
+func (chatView *ChatView) getLinkSourceMessages() []*discordgo.Message {
+	if chatView.selection >= 0 && chatView.selection < len(chatView.data) {
+		return []*discordgo.Message{chatView.data[chatView.selection]}
 	}
 
-	//TODO Support boost messages; Would be handy to see what they look like first.
-
-	//Might happen when there are unsupported types.
-	return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]message couldn't be rendered."
+	return chatView.GetVisibleMessages()
 }
 
-func (chatView *ChatView) formatDefaultMessageText(message *discordgo.Message) string {
-	messageText := tview.Escape(message.Content)
+// GetVisibleMessages returns all messages that are at least partially
+// visible in the current viewport.
+func (chatView *ChatView) GetVisibleMessages() []*discordgo.Message {
+	_, _, width, height := chatView.internalTextView.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
 
-	//Message.MentionRoles only contains the mentions for mentionable.
-	//Therefore we do it like this, in order to render every mention.
-	messageText = roleMentionRegex.
-		ReplaceAllStringFunc(messageText, func(data string) string {
+	firstVisibleRow, _ := chatView.internalTextView.GetScrollOffset()
+	firstVisibleRow = maths.Max(0, firstVisibleRow)
+	lastVisibleRow := firstVisibleRow + height - 1
+
+	var visibleMessages []*discordgo.Message
+	//The first row is the empty line in front of the first newline.
+	row := 1
+	for index, message := range chatView.data {
+		//Delimiters in front of the message are counted as part of it.
+		messageStart := row
+		row += countWrappedLines(chatView.renderedMessages[index], width)
+		if messageStart > lastVisibleRow {
+			break
+		}
+
+		if row > firstVisibleRow {
+			visibleMessages = append(visibleMessages, message)
+		}
+	}
+
+	return visibleMessages
+}
+
+// countWrappedLines returns the amount of lines that the text following the
+// first newline of the given text takes up, given the width of the TextView.
+func countWrappedLines(text string, width int) int {
+	if text == "" {
+		return 0
+	}
+
+	var lineCount int
+	for _, line := range strings.Split(text, "\n")[1:] {
+		stripped, _ := stripTags(line)
+		lineCount += maths.Max(1, len(tview.WordWrap(tview.Escape(stripped), width)))
+	}
+	return lineCount
+}
+
+// calculateContentHeight returns the amount of lines the current text would
+// take up in the TextView, given the width of the TextView.
+func (chatView *ChatView) calculateContentHeight(width int) int {
+	if width <= 0 {
+		return 0
+	}
+
+	return len(tview.WordWrap(chatView.internalTextView.GetText(true), width))
+}
+
+// Rerender renders all messages again, using the cache of formatted
+// messages, and fills the TextView with the result.
+func (chatView *ChatView) Rerender() {
+	chatView.renderedWidth = chatView.getWidth()
+	chatView.renderedMessages = make([]string, len(chatView.data))
+	followUps := chatView.getFollowUpTypes(chatView.data)
+	for index := range chatView.data {
+		chatView.renderedMessages[index] = chatView.renderMessage(index, followUps[index])
+	}
+	chatView.writeRenderedMessages()
+}
+
+// rerenderMessages renders the messages at the given indices again and fills
+// the TextView with all cached renderedMessages. Indices that are out of
+// range are ignored. If the width of the TextView has changed, all messages
+// are rendered again.
+func (chatView *ChatView) rerenderMessages(indices ...int) {
+	if chatView.renderedWidth != chatView.getWidth() {
+		chatView.Rerender()
+		return
+	}
+
+	followUps := chatView.getFollowUpTypes(chatView.data)
+	for _, index := range indices {
+		if index >= 0 && index < len(chatView.data) {
+			chatView.renderedMessages[index] = chatView.renderMessage(index, followUps[index])
+		}
+	}
+	chatView.writeRenderedMessages()
+}
+
+// renderMessage returns the text that the message at the given index is
+// written into the TextView with. The message is wrapped in a region named
+// after its ID, so that the text stays valid if other messages are added or
+// removed.
+func (chatView *ChatView) renderMessage(index int, followUp followUpType) string {
+	formattedMessage := chatView.getOrFormatMessage(chatView.data[index], followUp)
+	return chatView.ReturnDateDelimiter(chatView.data, index) +
+		chatView.ReturnUnreadDelimiter(chatView.data, index) +
+		"\n[\"" + chatView.data[index].ID + "\"]" + chatView.applyFindHighlights(index, formattedMessage)
+}
+
+// writeRenderedMessages replaces the content of the TextView with the
+// renderedMessages. The TextView can only append text or be cleared, so
+// changing a single message still requires writing all messages again. Only
+// the formatting of the unchanged messages is saved.
+func (chatView *ChatView) writeRenderedMessages() {
+	chatView.internalTextView.Clear()
+	//Writing the messages one by one is a lot faster than writing a single
+	//big text, as the TextView applies regular expressions to each write,
+	//which are considerably slower on long texts.
+	for _, renderedMessage := range chatView.renderedMessages {
+		fmt.Fprint(chatView.internalTextView, renderedMessage)
+	}
+}
+
+func (chatView *ChatView) getWidth() int {
+	_, _, width, _ := chatView.internalTextView.GetInnerRect()
+	return width
+}
+
+// findMatch is a single occurrence of the find query in a message.
+type findMatch struct {
+	messageIndex int
+	occurrence   int
+}
+
+// startFind enables the find mode, allowing the user to type a query.
+func (chatView *ChatView) startFind() {
+	chatView.findMode = true
+	chatView.updateFindTitle()
+}
+
+// StopFind leaves the find mode and removes all highlighted matches.
+func (chatView *ChatView) StopFind() {
+	chatView.findMode = false
+	chatView.findQuery = ""
+	chatView.findRegex = nil
+	chatView.internalTextView.SetTitle(chatView.title)
+	chatView.Rerender()
+}
+
+func (chatView *ChatView) handleFindInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		chatView.findMode = false
+		if chatView.findQuery == "" {
+			chatView.StopFind()
+		} else {
+			chatView.updateFindTitle()
+		}
+	case tcell.KeyEsc:
+		chatView.StopFind()
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if chatView.findQuery != "" {
+			queryRunes := []rune(chatView.findQuery)
+			chatView.setFindQuery(string(queryRunes[:len(queryRunes)-1]))
+		}
+	case tcell.KeyRune:
+		chatView.setFindQuery(chatView.findQuery + string(event.Rune()))
+	}
+
+	//While typing the query, no other shortcuts should be triggered.
+	return nil
+}
+
+// setFindQuery updates the query and jumps to the newest message matching it.
+func (chatView *ChatView) setFindQuery(query string) {
+	chatView.findQuery = query
+	if query == "" {
+		chatView.findRegex = nil
+	} else {
+		chatView.findRegex = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	}
+
+	//Since the highlighted matches in all messages change, every message
+	//has to be rendered again.
+	chatView.selectFindMatch(len(chatView.findMatches()) - 1)
+	chatView.Rerender()
+	chatView.updateHighlights()
+}
+
+// jumpToFindMatch selects the message containing the match at the given index.
+// Indices outside of the range of matches wrap around.
+func (chatView *ChatView) jumpToFindMatch(index int) {
+	previousMatchMessage := chatView.currentFindMatchLocation.messageIndex
+	chatView.selectFindMatch(index)
+	chatView.rerenderMessages(previousMatchMessage, chatView.currentFindMatchLocation.messageIndex)
+	chatView.updateHighlights()
+}
+
+// selectFindMatch updates the current match without rendering anything.
+func (chatView *ChatView) selectFindMatch(index int) {
+	matches := chatView.findMatches()
+	if len(matches) == 0 {
+		chatView.currentFindMatch = 0
+		chatView.currentFindMatchLocation = findMatch{messageIndex: -1}
+	} else {
+		chatView.currentFindMatch = (index%len(matches) + len(matches)) % len(matches)
+		chatView.currentFindMatchLocation = matches[chatView.currentFindMatch]
+		chatView.selection = chatView.currentFindMatchLocation.messageIndex
+	}
+
+	chatView.updateFindTitle()
+}
+
+func (chatView *ChatView) updateFindTitle() {
+	title := "/" + tview.Escape(chatView.findQuery)
+	if chatView.findQuery != "" {
+		matchCount := len(chatView.findMatches())
+		if matchCount == 0 {
+			title += " (no matches)"
+		} else {
+			title += fmt.Sprintf(" (%d/%d)", chatView.currentFindMatch+1, matchCount)
+		}
+	}
+
+	chatView.internalTextView.SetTitle(title)
+}
+
+// findMatches returns all occurrences of the find query in the currently
+// displayed messages, ordered from the oldest to the newest message.
+func (chatView *ChatView) findMatches() []findMatch {
+	var matches []findMatch
+	if chatView.findRegex == nil {
+		return matches
+	}
+
+	for index, message := range chatView.data {
+		strippedText, _ := stripTags(chatView.formattedMessages[message.ID])
+		occurrences := len(chatView.findRegex.FindAllStringIndex(strippedText, -1))
+		for occurrence := 0; occurrence < occurrences; occurrence++ {
+			matches = append(matches, findMatch{messageIndex: index, occurrence: occurrence})
+		}
+	}
+
+	return matches
+}
+
+// applyFindHighlights highlights all matches of the find query in the given
+// formatted message text. If no query is present, the text is returned as is.
+func (chatView *ChatView) applyFindHighlights(messageIndex int, text string) string {
+	if chatView.findRegex == nil {
+		return text
+	}
+
+	currentOccurrence := -1
+	if chatView.currentFindMatchLocation.messageIndex == messageIndex {
+		currentOccurrence = chatView.currentFindMatchLocation.occurrence
+	}
+
+	return highlightOccurrences(text, chatView.findRegex, currentOccurrence)
+}
+
+// highlightOccurrences changes the background color of every match of the
+// regex in the text. The tags in the text are ignored during the search. The
+// occurrence with the given index is highlighted using a different color.
+// After each match, the background that was in effect before is restored.
+func highlightOccurrences(text string, regex *regexp.Regexp, currentOccurrence int) string {
+	strippedText, offsets := stripTags(text)
+	occurrences := regex.FindAllStringIndex(strippedText, -1)
+
+	matchColor := "[:" + tviewutil.ColorToHex(config.GetTheme().FindMatchColor) + "]"
+	currentMatchColor := "[:" + tviewutil.ColorToHex(config.GetTheme().CurrentFindMatchColor) + "]"
+
+	//Inserting from back to front, so that the offsets stay valid.
+	for index := len(occurrences) - 1; index >= 0; index-- {
+		occurrence := occurrences[index]
+		if occurrence[0] == occurrence[1] {
+			continue
+		}
+
+		start := offsets[occurrence[0]]
+		end := offsets[occurrence[1]-1] + 1
+		color := matchColor
+		if index == currentOccurrence {
+			color = currentMatchColor
+		}
+
+		//Only the background is restored, since resetting it to the
+		//default would clobber the background the match is wrapped in.
+		text = text[:start] + color + text[start:end] + "[:" + backgroundAt(text, end) + "]" + text[end:]
+	}
+
+	return text
+}
+
+// backgroundAt returns the background colour that is in effect at the given
+// offset of the text, as it'd be written in a colour tag. If no background
+// has been set, "-" is returned, which resets the background.
+func backgroundAt(text string, offset int) string {
+	background := "-"
+	for _, tag := range tagRegex.FindAllStringSubmatchIndex(text[:offset], -1) {
+		if tag[6] != -1 {
+			background = text[tag[6]:tag[7]]
+		}
+	}
+
+	return background
+}
+
+// stripTags removes all colour and region tags from the given text. In
+// addition to the stripped text, the offset in the original text is returned
+// for each byte of the stripped text.
+func stripTags(text string) (string, []int) {
+	hidden := make([]bool, len(text))
+	for _, tag := range tagRegex.FindAllStringIndex(text, -1) {
+		//Empty brackets aren't treated as tags by tview either.
+		if tag[1]-tag[0] == 2 {
+			continue
+		}
+
+		for index := tag[0]; index < tag[1]; index++ {
+			hidden[index] = true
+		}
+	}
+
+	//Escaped tags contain an additional opening bracket that isn't shown.
+	for _, escaped := range escapedTagRegex.FindAllStringIndex(text, -1) {
+		hidden[escaped[1]-2] = true
+	}
+
+	var stripped strings.Builder
+	offsets := make([]int, 0, len(text))
+	for index := 0; index < len(text); index++ {
+		if !hidden[index] {
+			stripped.WriteByte(text[index])
+			offsets = append(offsets, index)
+		}
+	}
+
+	return stripped.String(), offsets
+}
+
+func (chatView *ChatView) formatMessage(message *discordgo.Message, details formattingDetails) string {
+	var member *discordgo.Member
+	if message.GuildID != "" {
+		member, _ = chatView.state.Member(message.GuildID, message.Author.ID)
+	}
+
+	var nick string
+	if member != nil {
+		nick = discordutil.GetMemberName(member)
+	} else {
+		nick = discordutil.GetUserName(message.Author)
+	}
+
+	var channelName string
+	channel, cacheError := chatView.state.Channel(message.ChannelID)
+	if cacheError == nil {
+		if channel.Type == discordgo.ChannelTypeGuildText {
+			channelName = tview.Escape(channel.Name)
+		} else {
+			channelName = tview.Escape(discordutil.GetPrivateChannelName(channel))
+		}
+	}
+
+	content := chatView.formatMessageText(message)
+	//Default messages are marked by the markdownRenderer.
+	if message.Type != discordgo.MessageTypeDefault && chatView.deletedMessageIDs[message.ID] {
+		content += " " + deletedMarker()
+	}
+
+	return chatView.applyMessageTemplate(details, map[string]string{
+		"author":        chatView.getUserColor(message.GuildID, message.Author, member) + alignToAuthorColumn(nick),
+		"nick":          alignToAuthorColumn(nick),
+		"username":      alignToAuthorColumn(discordutil.GetUserName(message.Author)),
+		"discriminator": message.Author.Discriminator,
+		"rolecolor":     chatView.getRoleColor(message.GuildID, member),
+		"channel":       channelName,
+		"content":       content,
+	})
+}
+
+// getUserColor returns a colour tag for the author of a message. If role
+// colours are enabled, the colour of the members highest coloured role is
+// used. The member may be nil for private channels.
+func (chatView *ChatView) getUserColor(guildID string, user *discordgo.User, member *discordgo.Member) string {
+	if config.GetConfig().UseRoleColors && member != nil {
+		guild, cacheError := chatView.state.Guild(guildID)
+		if cacheError == nil {
+			if roleColor := discordutil.GetRoleColor(member, guild.Roles); roleColor != 0 {
+				return roleColorToTag(roleColor)
+			}
+		}
+	}
+
+	if config.GetConfig().UseRandomUserColors {
+		return "[" + discordutil.GetUserColor(user) + "]"
+	}
+
+	return "[" + tviewutil.ColorToHex(config.GetTheme().DefaultUserColor) + "]"
+}
+
+// getRoleColor returns a colour tag for the colour of the highest role of the
+// member that has a colour. If there's no such role, the default user colour
+// is returned.
+func (chatView *ChatView) getRoleColor(guildID string, member *discordgo.Member) string {
+	if member != nil {
+		guild, cacheError := chatView.state.Guild(guildID)
+		if cacheError == nil {
+			if roleColor := discordutil.GetRoleColor(member, guild.Roles); roleColor != 0 {
+				return roleColorToTag(roleColor)
+			}
+		}
+	}
+
+	return "[" + tviewutil.ColorToHex(config.GetTheme().DefaultUserColor) + "]"
+}
+
+// roleColorToTag creates a colour tag for the given role colour, using the
+// closest colour that the terminal can display.
+func roleColorToTag(roleColor int) string {
+	return "[" + tviewutil.ColorToHex(tviewutil.NearestTerminalColor(tcell.NewHexColor(int32(roleColor)))) + "]"
+}
+
+// alignToAuthorColumn right-aligns the name inside of the author column. If
+// the name is too long for the column, it is shortened. Names are returned
+// unchanged if the author column is disabled.
+func alignToAuthorColumn(name string) string {
+	columnWidth := config.GetConfig().AuthorColumnWidth
+	if columnWidth <= 0 {
+		return name
+	}
+
+	nameWidth := tview.TaggedStringWidth(name)
+	if nameWidth > columnWidth {
+		nameRunes := []rune(name)
+		for len(nameRunes) > 0 && tview.TaggedStringWidth(string(nameRunes)) > columnWidth-1 {
+			nameRunes = nameRunes[:len(nameRunes)-1]
+		}
+		return string(nameRunes) + "…"
+	}
+
+	return strings.Repeat(" ", columnWidth-nameWidth) + name
+}
+
+// applyMessageTemplate fills the configured message template with the given
+// values. The time is taken from the details and is replaced with whitespace
+// for compacted messages. For grouped messages, everything in front of the
+// content is replaced with whitespace. Placeholders without a value are left
+// empty.
+func (chatView *ChatView) applyMessageTemplate(details formattingDetails, values map[string]string) string {
+	template := config.GetConfig().MessageTemplate
+	if template == "" {
+		template = config.DefaultMessageTemplate
+	}
+
+	timeText := tview.Escape(details.timeText)
+	if details.followUp == compactFollowUp {
+		timeText = strings.Repeat(" ", tview.TaggedStringWidth(timeText))
+	}
+
+	primaryColor := "[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]"
+	var builder strings.Builder
+	builder.WriteString(primaryColor)
+	lastPlaceholderEnd := 0
+	for _, placeholder := range placeholderRegex.FindAllStringSubmatchIndex(template, -1) {
+		builder.WriteString(tview.Escape(template[lastPlaceholderEnd:placeholder[0]]))
+		lastPlaceholderEnd = placeholder[1]
+
+		switch name := template[placeholder[2]:placeholder[3]]; name {
+		case "time":
+			builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().MessageTimeColor) + "]" + timeText + primaryColor)
+		case "author":
+			builder.WriteString(values[name] + primaryColor)
+		case "content":
+			if details.followUp == groupedFollowUp {
+				headerWidth := tview.TaggedStringWidth(builder.String())
+				builder.Reset()
+				builder.WriteString(strings.Repeat(" ", headerWidth))
+			}
+			builder.WriteString(primaryColor + values[name])
+		default:
+			builder.WriteString(values[name])
+		}
+	}
+	builder.WriteString(tview.Escape(template[lastPlaceholderEnd:]))
+	builder.WriteString("[\"\"][\"\"]")
+
+	return builder.String()
+}
+
+func (chatView *ChatView) formatMessageText(message *discordgo.Message) string {
+	if message.Type == discordgo.MessageTypeDefault {
+		return chatView.formatDefaultMessageText(message)
+	} else if message.Type == discordgo.MessageTypeGuildMemberJoin {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]joined the server."
+	} else if message.Type == discordgo.MessageTypeCall {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]has started a call."
+	} else if message.Type == discordgo.MessageTypeChannelIconChange {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]changed the channel icon."
+	} else if message.Type == discordgo.MessageTypeChannelNameChange {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]changed the channel name to " + message.Content + "."
+	} else if message.Type == discordgo.MessageTypeChannelPinnedMessage {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]pinned a message."
+	} else if message.Type == discordgo.MessageTypeRecipientAdd {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]added " + message.Mentions[0].Username + " to the group."
+	} else if message.Type == discordgo.MessageTypeRecipientRemove {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]removed " + message.Mentions[0].Username + " from the group."
+	} else if message.Type == discordgo.MessageTypeChannelFollowAdd {
+		return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]has added '" + message.Content + "' to this channel"
+	}
+
+	//TODO Support boost messages; Would be handy to see what they look like first.
+
+	//Might happen when there are unsupported types.
+	return "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]message couldn't be rendered."
+}
+
+func (chatView *ChatView) formatDefaultMessageText(message *discordgo.Message) string {
+	renderer := &markdownRenderer{
+		chatView: chatView,
+		message:  message,
+	}
+
+	isDeleted := chatView.deletedMessageIDs[message.ID]
+	if isDeleted {
+		switch config.GetConfig().DeletedMessageBehaviour {
+		case config.StrikeThroughDeletedMessages:
+			renderer.strikethroughDepth++
+		case config.DimDeletedMessages:
+			renderer.attributes = append(renderer.attributes, 'd')
+			renderer.write(renderer.attributesTag())
+		}
+	}
+
+	renderer.renderNodes(markdown.Parse(message.Content))
+
+	if len(renderer.attributes) > 0 {
+		renderer.attributes = nil
+		renderer.write(renderer.attributesTag())
+	}
+
+	if message.EditedTimestamp != "" {
+		renderer.writeMarker("[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "](edited)[" +
+			tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]")
+	}
+
+	if isDeleted {
+		renderer.writeMarker(deletedMarker())
+	}
+
+	// FIXME Needs improvement, as it wastes space and breaks things
+	if message.Attachments != nil && len(message.Attachments) > 0 {
+		var attachments []string
+		for _, attachment := range message.Attachments {
+			attachments = append(attachments, attachment.URL)
+		}
+
+		if renderer.builder.Len() > 0 {
+			renderer.write("\n")
+		}
+		renderer.renderText(strings.Join(attachments, " "))
+	}
+
+	return renderer.builder.String()
+}
+
+// markdownRenderer turns the nodes of a parsed message into text containing
+// tview tags.
+type markdownRenderer struct {
+	chatView *ChatView
+	message  *discordgo.Message
+	builder  strings.Builder
+
+	// attributes contains the text attributes of all currently open
+	// formatting nodes, for example 'b' for bold text.
+	attributes []byte
+	// strikethroughDepth is greater than zero while rendering text that
+	// should be struck through.
+	strikethroughDepth int
+	// quotePrefix is written at the start of each line inside of quotes.
+	quotePrefix string
+	// codeBlockEnded is true if the last thing written was a codeblock. In
+	// that case anything following has to start in a new line.
+	codeBlockEnded bool
+}
+
+func (renderer *markdownRenderer) write(text string) {
+	if text == "" {
+		return
+	}
+
+	if renderer.codeBlockEnded && text[0] != '\n' {
+		renderer.builder.WriteByte('\n')
+	}
+	renderer.codeBlockEnded = false
+	renderer.builder.WriteString(text)
+}
+
+// writeMarker appends a note, like "(edited)", to the message.
+func (renderer *markdownRenderer) writeMarker(marker string) {
+	if renderer.builder.Len() > 0 && !renderer.codeBlockEnded {
+		renderer.write(" ")
+	}
+	renderer.write(marker)
+}
+
+func deletedMarker() string {
+	return "[" + tviewutil.ColorToHex(config.GetTheme().ErrorColor) + "](deleted)[" +
+		tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]"
+}
+
+func (renderer *markdownRenderer) attributesTag() string {
+	if len(renderer.attributes) == 0 {
+		return "[::-]"
+	}
+
+	return "[::" + string(renderer.attributes) + "]"
+}
+
+func (renderer *markdownRenderer) renderNodes(nodes []*markdown.Node) {
+	for _, node := range nodes {
+		renderer.renderNode(node)
+	}
+}
+
+func (renderer *markdownRenderer) renderNode(node *markdown.Node) {
+	switch node.Type {
+	case markdown.Text, markdown.Escaped:
+		renderer.renderText(node.Content)
+	case markdown.Bold:
+		renderer.renderWithAttribute('b', node.Children)
+	case markdown.Underline:
+		renderer.renderWithAttribute('u', node.Children)
+	case markdown.Italic:
+		//Terminals can't reliably display italic text, therefore it's dimmed.
+		renderer.renderWithAttribute('d', node.Children)
+	case markdown.Strikethrough:
+		renderer.strikethroughDepth++
+		renderer.renderNodes(node.Children)
+		renderer.strikethroughDepth--
+	case markdown.Spoiler:
+		shouldShow, contains := renderer.chatView.showSpoilerContent[renderer.message.ID]
+		if contains && shouldShow {
+			renderer.write("||")
+			renderer.renderNodes(node.Children)
+			renderer.write("||")
+		} else {
+			renderer.write("[" + tviewutil.ColorToHex(config.GetTheme().AttentionColor) + "]!SPOILER![" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]")
+		}
+	case markdown.InlineCode:
+		renderer.write("[" + tviewutil.ColorToHex(config.GetTheme().InlineCodeColor) + "]" +
+			tview.Escape(node.Content) + "[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]")
+	case markdown.CodeBlock:
+		renderer.renderCodeBlock(node)
+	case markdown.BlockQuote:
+		oldQuotePrefix := renderer.quotePrefix
+		renderer.quotePrefix = "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]▐ [" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]"
+		renderer.write(renderer.quotePrefix)
+		renderer.renderNodes(node.Children)
+		renderer.quotePrefix = oldQuotePrefix
+	}
+}
+
+func (renderer *markdownRenderer) renderWithAttribute(attribute byte, children []*markdown.Node) {
+	renderer.attributes = append(renderer.attributes, attribute)
+	renderer.write(renderer.attributesTag())
+	renderer.renderNodes(children)
+	renderer.attributes = renderer.attributes[:len(renderer.attributes)-1]
+	renderer.write(renderer.attributesTag())
+}
+
+// renderText escapes the text and renders mentions and links.
+func (renderer *markdownRenderer) renderText(text string) {
+	text = renderer.chatView.formatMentions(renderer.message, tview.Escape(text))
+	text = renderer.chatView.shortenURLs(text)
+	if renderer.strikethroughDepth > 0 {
+		text = strikeThrough(text)
+	}
+
+	//Attributes and quotes have to be applied to each line again.
+	if len(renderer.attributes) > 0 || renderer.quotePrefix != "" {
+		linePrefix := renderer.quotePrefix
+		if len(renderer.attributes) > 0 {
+			linePrefix += renderer.attributesTag()
+		}
+		text = strings.Replace(text, "\n", "\n"+linePrefix, -1)
+	}
+
+	renderer.write(text)
+}
+
+func (renderer *markdownRenderer) renderCodeBlock(node *markdown.Node) {
+	//Remove all carriage returns to prevent bugs with windows newlines.
+	code := strings.ReplaceAll(node.Content, "\r", "")
+	//Remove last newline, as it's usually just the newline that seperates code from markdown notation.
+	code = strings.TrimSuffix(code, "\n")
+	code = removeLeadingWhitespaceInCode(code)
+	code = tview.Escape(code)
+
+	codeWithBars := highlightCode(node.Language, code)
+
+	//Codeblocks always start in a new line.
+	if !strings.HasSuffix(renderer.builder.String(), "\n") {
+		codeWithBars = "\n" + codeWithBars
+	}
+	renderer.write(codeWithBars)
+	renderer.codeBlockEnded = true
+}
+
+// highlightedCodeCacheKey identifies a highlighted piece of code, including
+// the settings it has been highlighted with.
+type highlightedCodeCacheKey struct {
+	language  string
+	style     string
+	formatter string
+	codeHash  [sha256.Size]byte
+}
+
+// maxHighlightedCodeCacheSize is the amount of highlighted pieces of code
+// that are kept in memory before the cache is cleared.
+const maxHighlightedCodeCacheSize = 500
+
+var (
+	highlightedCodeCache      = make(map[highlightedCodeCacheKey]string)
+	highlightedCodeCacheMutex = &sync.Mutex{}
+)
+
+// highlightCode applies syntax highlighting to the given code and prefixes
+// each line with a bar, indicating that it's part of a codeblock. The result
+// is cached, so that the same code isn't tokenised over and over again.
+func highlightCode(language, code string) string {
+	cacheKey := highlightedCodeCacheKey{
+		language:  language,
+		style:     config.GetTheme().SyntaxHighlightingStyle,
+		formatter: config.GetTheme().SyntaxHighlightingFormatter,
+		codeHash:  sha256.Sum256([]byte(code)),
+	}
+
+	highlightedCodeCacheMutex.Lock()
+	defer highlightedCodeCacheMutex.Unlock()
+
+	if cachedCode, contains := highlightedCodeCache[cacheKey]; contains {
+		return cachedCode
+	}
+
+	// Determine lexer.
+	l := lexers.Get(language)
+	if l == nil {
+		l = lexers.Fallback
+	}
+	l = chroma.Coalesce(l)
+
+	// Determine formatter.
+	f := formatters.Get(cacheKey.formatter)
+	if f == nil {
+		f = formatters.Fallback
+	}
+
+	// Determine style.
+	s := styles.Get(cacheKey.style)
+	if s == nil {
+		s = styles.Fallback
+	}
+
+	writer := bytes.NewBufferString("")
+	it, tokeniseError := l.Tokenise(nil, code)
+	if tokeniseError != nil {
+		writer.WriteString(code)
+	} else if formatError := f.Format(writer, s, it); formatError != nil {
+		writer.Reset()
+		writer.WriteString(code)
+	}
+
+	//Remove the last newline, as some formatters behave differently and don't drop it.
+	formattedCode := writer.String()
+	newLineDifference := strings.Count(formattedCode, "\n") - strings.Count(code, "\n")
+	for ; newLineDifference > 0; newLineDifference-- {
+		formattedCode = formattedCode[:(strings.LastIndex(formattedCode, "\n"))]
+	}
+
+	var codeWithBars, lastColor string
+	lines := strings.Split(formattedCode, "\n")
+	for index, line := range lines {
+		if index != 0 {
+			codeWithBars += "\n"
+			colorCodes := colorRegex.FindAllString(lines[index-1], -1)
+			if len(colorCodes) > 0 {
+				lastColor = colorCodes[len(colorCodes)-1]
+			}
+
+			if lastColor != "" {
+				codeWithBars += fmt.Sprintf("[#c9dddc]▐ %s%s", lastColor, line)
+				continue
+			}
+		}
+
+		codeWithBars += "[#c9dddc]▐ " + line
+	}
+
+	if len(highlightedCodeCache) >= maxHighlightedCodeCacheSize {
+		highlightedCodeCache = make(map[highlightedCodeCacheKey]string)
+	}
+	highlightedCodeCache[cacheKey] = codeWithBars
+
+	return codeWithBars
+}
+
+// strikeThrough adds a combining long stroke overlay to every character of
+// the text, ignoring tview tags and newlines.
+func strikeThrough(text string) string {
+	var builder strings.Builder
+	lastTagEnd := 0
+	strike := func(part string) {
+		for _, character := range part {
+			builder.WriteRune(character)
+			if character != '\n' {
+				builder.WriteRune('̶')
+			}
+		}
+	}
+
+	for _, tag := range tagRegex.FindAllStringIndex(text, -1) {
+		strike(text[lastTagEnd:tag[0]])
+		builder.WriteString(text[tag[0]:tag[1]])
+		lastTagEnd = tag[1]
+	}
+	strike(text[lastTagEnd:])
+
+	return builder.String()
+}
+
+// formatMentions replaces all user, role and channel mentions with their
+// coloured names.
+func (chatView *ChatView) formatMentions(message *discordgo.Message, messageText string) string {
+	//Message.MentionRoles only contains the mentions for mentionable.
+	//Therefore we do it like this, in order to render every mention.
+	messageText = roleMentionRegex.
+		ReplaceAllStringFunc(messageText, func(data string) string {
 			roleID := strings.TrimSuffix(strings.TrimPrefix(data, "<@&"), ">")
 			role, cacheError := chatView.state.Role(message.GuildID, roleID)
 			if cacheError != nil {
@@ -501,7 +1794,7 @@ func (chatView *ChatView) formatDefaultMessageText(message *discordgo.Message) s
 		).Replace(messageText)
 	}
 
-	messageText = channelMentionRegex.
+	return channelMentionRegex.
 		ReplaceAllStringFunc(messageText, func(data string) string {
 			channelID := strings.TrimSuffix(strings.TrimPrefix(data, "<#"), ">")
 			channel, cacheError := chatView.state.Channel(channelID)
@@ -511,134 +1804,30 @@ func (chatView *ChatView) formatDefaultMessageText(message *discordgo.Message) s
 
 			return "[" + tviewutil.ColorToHex(config.GetTheme().LinkColor) + "]#" + channel.Name + "[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]"
 		})
+}
 
-	// FIXME Needs improvement, as it wastes space and breaks things
-	if message.Attachments != nil && len(message.Attachments) > 0 {
-		var attachments []string
-		for _, attachment := range message.Attachments {
-			attachments = append(attachments, attachment.URL)
-		}
-		attachmentsAsText := strings.Join(attachments, " ")
-
-		if messageText != "" {
-			messageText = messageText + "\n" + attachmentsAsText
-		} else {
-			messageText = attachmentsAsText
-		}
-	}
-
+// shortenURLs replaces long links with shortened ones if link shortening
+// is enabled.
+func (chatView *ChatView) shortenURLs(messageText string) string {
 	// FIXME Handle Non-embed links nonetheless?
-	if chatView.shortenLinks {
-		urlMatches := urlRegex.FindAllStringSubmatch(messageText, 1000)
-
-		for _, urlMatch := range urlMatches {
-			newURL := urlMatch[1] + urlMatch[2]
-			if len(urlMatch) == 5 || (len(urlMatch) == 4 && len(urlMatch[3]) > 1) {
-				newURL = newURL + urlMatch[3]
-			}
-			if (len(urlMatch[2]) + 35) < len(newURL) {
-				newURL = fmt.Sprintf("(%s) %s", urlMatch[2], chatView.shortener.Shorten(newURL))
-			}
-			if len(urlMatch) == 5 {
-				newURL = newURL + strings.TrimSuffix(urlMatch[4], ">")
-			}
-			messageText = strings.Replace(messageText, urlMatch[0], newURL, 1)
-		}
+	if !chatView.shortenLinks {
+		return messageText
 	}
 
-	codeBlocks := codeBlockRegex.
-		// Magicnumber, because message aren't gonna be that long anyway.
-		FindAllStringSubmatch(messageText, 1000)
-
-	for _, values := range codeBlocks {
-		language := values[3]
-		code := values[4]
-
-		//Remove all carriage returns to prevent bugs with windows newlines.
-		code = strings.ReplaceAll(code, "\r", "")
-		//Remove last newline, as it's usually just the newline that seperates code from markdown notation.
-		code = strings.TrimSuffix(code, "\n")
-		code = removeLeadingWhitespaceInCode(code)
-
-		// Determine lexer.
-		l := lexers.Get(language)
-		if l == nil {
-			l = lexers.Fallback
+	urlMatches := urlRegex.FindAllStringSubmatch(messageText, 1000)
+	for _, urlMatch := range urlMatches {
+		newURL := urlMatch[1] + urlMatch[2]
+		if len(urlMatch) == 5 || (len(urlMatch) == 4 && len(urlMatch[3]) > 1) {
+			newURL = newURL + urlMatch[3]
 		}
-		l = chroma.Coalesce(l)
-
-		// Determine formatter.
-		f := formatters.Get("tview-8bit")
-		if f == nil {
-			f = formatters.Fallback
-		}
-
-		// Determine style.
-		s := styles.Get("monokai")
-		if s == nil {
-			s = styles.Fallback
-		}
-
-		it, tokeniseError := l.Tokenise(nil, code)
-		if tokeniseError != nil {
-			continue
-		}
-
-		writer := bytes.NewBufferString("")
-
-		formatError := f.Format(writer, s, it)
-		if formatError != nil {
-			continue
-		}
-
-		//Remove the last newline, as some formatters behave differently and don't drop it.
-		escapedCode := strings.NewReplacer("*", "\\*", "_", "\\_", "|", "\\|").Replace(writer.String())
-		newLineDifference := strings.Count(escapedCode, "\n") - strings.Count(code, "\n")
-		if newLineDifference > 0 {
-			for ; newLineDifference != 0; newLineDifference-- {
-				escapedCode = escapedCode[:(strings.LastIndex(escapedCode, "\n"))]
-			}
-		}
-		var formattedCode, lastColor string
-		lines := strings.Split(escapedCode, "\n")
-		for index, line := range lines {
-			if index != 0 {
-				formattedCode += "\n"
-				colorCodes := colorRegex.FindAllString(lines[index-1], -1)
-				if len(colorCodes) > 0 {
-					lastColor = colorCodes[len(colorCodes)-1]
-				}
-
-				if lastColor != "" {
-					formattedCode += fmt.Sprintf("[#c9dddc]▐ %s%s", lastColor, line)
-					continue
-				}
-			}
-
-			formattedCode += "[#c9dddc]▐ " + line
-		}
-
-		beforeCodeBlock := values[1]
-		if beforeCodeBlock != "\n" {
-			formattedCode = "\n" + formattedCode
+		if (len(urlMatch[2]) + 35) < len(newURL) {
+			newURL = fmt.Sprintf("(%s) %s", urlMatch[2], chatView.shortener.Shorten(newURL))
 		}
-		afterCodeBlock := values[5]
-		if len(afterCodeBlock) != 0 && afterCodeBlock != "\n" {
-			formattedCode = formattedCode + "\n"
+		if len(urlMatch) == 5 {
+			newURL = newURL + strings.TrimSuffix(urlMatch[4], ">")
 		}
-
-		messageText = strings.Replace(messageText, values[2], formattedCode, 1)
-	}
-
-	messageText = strings.
-		NewReplacer("\\*", "*", "\\_", "_", "\\`", "`").
-		Replace(parseBoldAndUnderline(messageText))
-
-	shouldShow, contains := chatView.showSpoilerContent[message.ID]
-	if !contains || !shouldShow {
-		messageText = spoilerRegex.ReplaceAllString(messageText, "["+tviewutil.ColorToHex(config.GetTheme().AttentionColor)+"]!SPOILER!["+tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor)+"]")
+		messageText = strings.Replace(messageText, urlMatch[0], newURL, 1)
 	}
-	messageText = strings.Replace(messageText, "\\|", "|", -1)
 
 	return messageText
 }
@@ -691,143 +1880,129 @@ func removeLeadingWhitespaceInCode(code string) string {
 	return tabsTrimmed
 }
 
-func (chatView *ChatView) messagePartsToColouredString(timestamp discordgo.Timestamp, author, message string) string {
-	time, parseError := timestamp.Parse()
-	var timeCellText string
-	if parseError == nil {
-		timeCellText = times.TimeToLocalString(&time)
+// ClearSelection clears the current selection of messages.
+func (chatView *ChatView) ClearSelection() {
+	chatView.selection = -1
+	chatView.updateHighlights()
+}
+
+// SignalSelectionDeleted notifies the ChatView that its currently selected
+// message doesn't exist anymore, moving the selection up by a row if possible.
+// If deleted messages are kept in the view, the selection stays the same.
+func (chatView *ChatView) SignalSelectionDeleted() {
+	if chatView.selection > 0 &&
+		config.GetConfig().DeletedMessageBehaviour == config.RemoveDeletedMessages {
+		chatView.selection--
 	}
+}
 
-	return fmt.Sprintf("["+tviewutil.ColorToHex(config.GetTheme().MessageTimeColor)+"]%s %s ["+tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor)+"]%s[\"\"][\"\"]", timeCellText, author, message)
+// ChatViewState is the part of the ChatViews state that belongs to the loaded
+// channel, allowing to restore it after loading the channel again.
+type ChatViewState struct {
+	selectedMessageID string
+	// firstVisibleMessageID is empty if the view was scrolled to the end.
+	firstVisibleMessageID string
+	// messages are all loaded messages, so that older messages that have
+	// been loaded by scrolling up don't have to be requested again.
+	messages            []*discordgo.Message
+	reachedChannelStart bool
 }
 
-func parseBoldAndUnderline(messageText string) string {
-	messageTextTemp := make([]rune, 0)
+// GetState returns the current selection, scroll position and messages.
+func (chatView *ChatView) GetState() *ChatViewState {
+	state := &ChatViewState{
+		messages:            append([]*discordgo.Message(nil), chatView.data...),
+		reachedChannelStart: chatView.reachedChannelStart,
+	}
+	if chatView.selection >= 0 && chatView.selection < len(chatView.data) {
+		state.selectedMessageID = chatView.data[chatView.selection].ID
+	}
 
-	firstBoldFound := false
-	boldOpen := false
-	firstUnderlineFound := false
-	underlineOpen := false
+	if !chatView.internalTextView.IsScrolledToEnd() {
+		visibleMessages := chatView.GetVisibleMessages()
+		if len(visibleMessages) > 0 {
+			state.firstVisibleMessageID = visibleMessages[0].ID
+		}
+	}
 
-	runes := []rune(messageText)
-	lastIndex := len(runes) - 1
-	for index, character := range runes {
-		messageTextTemp = append(messageTextTemp, character)
-		if character == '\n' {
-			if boldOpen && !underlineOpen {
-				messageTextTemp = append(messageTextTemp, '[', ':', ':', 'b', ']')
-			} else if !boldOpen && underlineOpen {
-				messageTextTemp = append(messageTextTemp, '[', ':', ':', 'u', ']')
-			} else if boldOpen && underlineOpen {
-				messageTextTemp = append(messageTextTemp, '[', ':', ':', 'u', 'b', ']')
-			}
-		} else if character == '*' {
-			if firstBoldFound {
-				firstBoldFound = false
-				if boldOpen {
-					boldOpen = false
-					if underlineOpen {
-						messageTextTemp = append(messageTextTemp[:len(messageTextTemp)-2], '[', ':', ':', 'u', ']')
-					} else {
-						messageTextTemp = append(messageTextTemp[:len(messageTextTemp)-2], '[', ':', ':', '-', ']')
-					}
-				} else if index != lastIndex {
-					doesClosingOneExist := false
-					foundFirstClosing := false
-					for _, c := range runes[index+1:] {
-						if c == '*' {
-							if foundFirstClosing {
-								doesClosingOneExist = true
-								break
-							}
-							foundFirstClosing = true
-						}
-					}
-
-					if doesClosingOneExist {
-						if runes[index+1] == '*' {
-							firstBoldFound = true
-							continue
-						}
-
-						boldOpen = true
-						messageTextTemp = append(messageTextTemp[:len(messageTextTemp)-2], '[', ':', ':')
-						if underlineOpen {
-							messageTextTemp = append(messageTextTemp, 'u')
-						}
-						messageTextTemp = append(messageTextTemp, 'b', ']')
-					}
-				}
-			} else {
-				firstBoldFound = true
-			}
-		} else if character == '_' {
-			if firstUnderlineFound {
-				firstUnderlineFound = false
-				if underlineOpen {
-					underlineOpen = false
-					if boldOpen {
-						messageTextTemp = append(messageTextTemp[:len(messageTextTemp)-2], '[', ':', ':', 'b', ']')
-					} else {
-						messageTextTemp = append(messageTextTemp[:len(messageTextTemp)-2], '[', ':', ':', '-', ']')
-					}
-				} else if index != lastIndex {
-					doesClosingOneExist := false
-					foundFirstClosing := false
-					for _, c := range runes[index+1:] {
-						if c == '_' {
-							if foundFirstClosing {
-								doesClosingOneExist = true
-								break
-							}
-							foundFirstClosing = true
-						}
-					}
-
-					if doesClosingOneExist {
-						if runes[index+1] == '_' {
-							firstUnderlineFound = true
-							continue
-						}
-
-						underlineOpen = true
-						messageTextTemp = append(messageTextTemp[:len(messageTextTemp)-2], '[', ':', ':')
-						if boldOpen {
-							messageTextTemp = append(messageTextTemp, 'b')
-						}
-						messageTextTemp = append(messageTextTemp, 'u', ']')
-					}
-				}
-			} else {
-				firstUnderlineFound = true
+	return state
+}
+
+// RestoreState adds the previously loaded messages that are older than the
+// current messages, selects the previously selected message and scrolls back
+// to the previously visible messages. Messages that aren't part of the view
+// anymore are ignored. This should be called after setting the messages.
+func (chatView *ChatView) RestoreState(state *ChatViewState) {
+	olderMessages := state.messages
+	if len(chatView.data) > 0 {
+		firstTime, _ := chatView.data[0].Timestamp.Parse()
+		olderMessages = nil
+		for _, message := range state.messages {
+			messageTime, _ := message.Timestamp.Parse()
+			if messageTime.Before(firstTime) {
+				olderMessages = append(olderMessages, message)
 			}
-		} else {
-			firstBoldFound = false
-			firstUnderlineFound = false
 		}
 	}
+	if len(olderMessages) > 0 {
+		chatView.PrependMessages(olderMessages)
+		chatView.reachedChannelStart = state.reachedChannelStart
+	}
 
-	return string(messageTextTemp)
-}
+	//The highlight is set without scrolling to it, as that would override
+	//the restored scroll position the next time the view is drawn.
+	for index, message := range chatView.data {
+		if message.ID == state.selectedMessageID {
+			chatView.selection = index
+			chatView.internalTextView.Highlight(message.ID)
+			break
+		}
+	}
 
-// ClearSelection clears the current selection of messages.
-func (chatView *ChatView) ClearSelection() {
-	chatView.selection = -1
-	chatView.updateHighlights()
+	if state.firstVisibleMessageID == "" {
+		chatView.internalTextView.ScrollToEnd()
+		return
+	}
+
+	width := chatView.getWidth()
+	//The first row is the empty line in front of the first newline.
+	row := 1
+	for index, message := range chatView.data {
+		if message.ID == state.firstVisibleMessageID {
+			chatView.internalTextView.ScrollTo(row, 0)
+			break
+		}
+		row += countWrappedLines(chatView.renderedMessages[index], width)
+	}
 }
 
-// SignalSelectionDeleted notifies the ChatView that its currently selected
-// message doesn't exist anymore, moving the selection up by a row if possible.
-func (chatView *ChatView) SignalSelectionDeleted() {
-	if chatView.selection > 0 {
-		chatView.selection--
+// SelectMessage selects the message with the given ID and scrolls it to the
+// top of the view. If the message isn't part of the view, false is returned.
+func (chatView *ChatView) SelectMessage(messageID string) bool {
+	for _, message := range chatView.data {
+		if message.ID == messageID {
+			chatView.RestoreState(&ChatViewState{
+				selectedMessageID:     messageID,
+				firstVisibleMessageID: messageID,
+			})
+			return true
+		}
 	}
+
+	return false
 }
 
 // SetMessages defines all currently displayed messages. Parsing and
 // manipulation of single message elements happens in this function.
 func (chatView *ChatView) SetMessages(messages []*discordgo.Message) {
 	chatView.data = make([]*discordgo.Message, 0)
+	chatView.bufferSize = maths.Max(defaultBufferSize, len(messages))
+	chatView.loadingOlderMessages = false
+	chatView.reachedChannelStart = false
+	chatView.oldestMessageID = ""
+	chatView.deletedMessageIDs = make(map[string]bool)
+	chatView.renderedMessages = nil
+	chatView.renderedWidth = chatView.getWidth()
 	chatView.internalTextView.SetText("")
 
 	chatView.AddMessages(messages)
@@ -1,15 +1,28 @@
 package ui
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Bios-Marcel/cordless/config"
 	_ "github.com/Bios-Marcel/cordless/syntax"
+	"github.com/Bios-Marcel/cordless/times"
 	"github.com/Bios-Marcel/cordless/ui/tviewutil"
 	"github.com/Bios-Marcel/discordgo"
+	"github.com/gdamore/tcell"
 )
 
-func TestParseBoldAndUnderline(t *testing.T) {
+func TestChatView_formatBoldAndUnderline(t *testing.T) {
+	chatView := &ChatView{
+		showSpoilerContent: make(map[string]bool),
+		state:              &discordgo.State{},
+	}
 	tests := []struct {
 		name  string
 		input string
@@ -93,8 +106,8 @@ func TestParseBoldAndUnderline(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := parseBoldAndUnderline(tt.input); got != tt.want {
-				t.Errorf("ParseBoldAndUnderline() = '%v', want '%v'", got, tt.want)
+			if got := chatView.formatMessageText(&discordgo.Message{Content: tt.input}); got != tt.want {
+				t.Errorf("ChatView.formatMessageText() = '%v', want '%v'", got, tt.want)
 			}
 		})
 	}
@@ -353,6 +366,42 @@ func TestChatView_formatMessageText(t *testing.T) {
 			},
 			want:     "\n[#c9dddc]▐ [#ffffff]owo\nf\n[#c9dddc]▐ [#ffffff]owo",
 			chatView: defaultChatView,
+		}, {
+			name: "italic text",
+			input: &discordgo.Message{
+				Content: "*simple* _simple_",
+			},
+			want:     "[::d]simple[::-] [::d]simple[::-]",
+			chatView: defaultChatView,
+		}, {
+			name: "strikethrough text",
+			input: &discordgo.Message{
+				Content: "~~ab~~",
+			},
+			want:     "a\u0336b\u0336",
+			chatView: defaultChatView,
+		}, {
+			name: "inline code isn't formatted",
+			input: &discordgo.Message{
+				Content: "a `**b**` c",
+			},
+			want:     "a [" + tviewutil.ColorToHex(config.GetTheme().InlineCodeColor) + "]**b**[#ffffff] c",
+			chatView: defaultChatView,
+		}, {
+			name: "multiline quote with formatting",
+			input: &discordgo.Message{
+				Content: ">>> **a\nb**",
+			},
+			want: "[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]▐ [#ffffff][::b]a\n" +
+				"[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "]▐ [#ffffff][::b]b[::-]",
+			chatView: defaultChatView,
+		}, {
+			name: "text looking like tags is escaped",
+			input: &discordgo.Message{
+				Content: "**[red]**",
+			},
+			want:     "[::b][red[][::-]",
+			chatView: defaultChatView,
 		}, {
 			name: "Remove escape characters",
 			input: &discordgo.Message{
@@ -369,6 +418,22 @@ func TestChatView_formatMessageText(t *testing.T) {
 			},
 			want:     "\\`*_",
 			chatView: defaultChatView,
+		}, {
+			name: "edited message",
+			input: &discordgo.Message{
+				Content:         "**a**",
+				EditedTimestamp: "2019-10-10T10:00:00+00:00",
+			},
+			want:     "[::b]a[::-] [" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "](edited)[#ffffff]",
+			chatView: defaultChatView,
+		}, {
+			name: "edited message ending with codeblock",
+			input: &discordgo.Message{
+				Content:         "```\none\n```",
+				EditedTimestamp: "2019-10-10T10:00:00+00:00",
+			},
+			want:     "\n[#c9dddc]▐ [#ffffff]one\n[" + tviewutil.ColorToHex(config.GetTheme().InfoMessageColor) + "](edited)[#ffffff]",
+			chatView: defaultChatView,
 		},
 	}
 	for _, tt := range tests {
@@ -452,3 +517,773 @@ func Test_removeLeadingWhitespaceInCode(t *testing.T) {
 		})
 	}
 }
+
+func TestChatView_isFirstUnreadMessage(t *testing.T) {
+	chatView := &ChatView{}
+	chatView.SetUnreadMarker("100")
+
+	tests := []struct {
+		name            string
+		previousMessage *discordgo.Message
+		message         *discordgo.Message
+		want            bool
+	}{
+		{
+			name:            "read message",
+			previousMessage: &discordgo.Message{ID: "99"},
+			message:         &discordgo.Message{ID: "100"},
+			want:            false,
+		}, {
+			name:            "first unread message",
+			previousMessage: &discordgo.Message{ID: "100"},
+			message:         &discordgo.Message{ID: "101"},
+			want:            true,
+		}, {
+			name:            "second unread message",
+			previousMessage: &discordgo.Message{ID: "101"},
+			message:         &discordgo.Message{ID: "102"},
+			want:            false,
+		}, {
+			name:            "unread message without previous message",
+			previousMessage: nil,
+			message:         &discordgo.Message{ID: "101"},
+			want:            true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chatView.isFirstUnreadMessage(tt.previousMessage, tt.message); got != tt.want {
+				t.Errorf("ChatView.isFirstUnreadMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	chatView.SetUnreadMarker("")
+	if chatView.isFirstUnreadMessage(nil, &discordgo.Message{ID: "101"}) {
+		t.Error("ChatView.isFirstUnreadMessage() = true, but the marker had been cleared")
+	}
+}
+
+func Test_stripTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "no tags",
+			input: "Hello",
+			want:  "Hello",
+		}, {
+			name:  "color tags",
+			input: "[red]Hel[-]lo[::b]!",
+			want:  "Hello!",
+		}, {
+			name:  "region tag",
+			input: "[\"1\"]Hello[\"\"]",
+			want:  "Hello",
+		}, {
+			name:  "empty brackets",
+			input: "Hello []",
+			want:  "Hello []",
+		}, {
+			name:  "escaped tag",
+			input: "Hello [red[]",
+			want:  "Hello [red]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, offsets := stripTags(tt.input)
+			if got != tt.want {
+				t.Errorf("stripTags() = %v, want %v", got, tt.want)
+			}
+			for index := range got {
+				if got[index] != tt.input[offsets[index]] {
+					t.Errorf("offset %d points to '%c', want '%c'", index, tt.input[offsets[index]], got[index])
+				}
+			}
+		})
+	}
+}
+
+func Test_countWrappedLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  0,
+		}, {
+			name:  "single line",
+			input: "\n[\"1\"]Hello",
+			want:  1,
+		}, {
+			name:  "empty lines",
+			input: "\nHello\n\nWorld",
+			want:  3,
+		}, {
+			name:  "wrapped line",
+			input: "\n[red]Hello World",
+			want:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countWrappedLines(tt.input, 6); got != tt.want {
+				t.Errorf("countWrappedLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_highlightOccurrences(t *testing.T) {
+	regex := regexp.MustCompile("(?i)lo")
+	got := highlightOccurrences("[red]Hel[-]lo LO", regex, 1)
+	want := "[red]Hel[-][:#808000]lo[:-] [:#ffa500]LO[:-]"
+	if got != want {
+		t.Errorf("highlightOccurrences() = %v, want %v", got, want)
+	}
+
+	got = highlightOccurrences("[red]He[-]llo", regexp.MustCompile("el"), -1)
+	want = "[red]H[:#808000]e[-]l[:-]lo"
+	if got != want {
+		t.Errorf("highlightOccurrences() = %v, want %v", got, want)
+	}
+
+	//The surrounding background has to be kept.
+	got = highlightOccurrences("[:blue]Hello[:-] lo", regexp.MustCompile("lo"), -1)
+	want = "[:blue]Hel[:#808000]lo[:blue][:-] [:#808000]lo[:-]"
+	if got != want {
+		t.Errorf("highlightOccurrences() = %v, want %v", got, want)
+	}
+}
+
+func Test_alignToAuthorColumn(t *testing.T) {
+	oldWidth := config.GetConfig().AuthorColumnWidth
+	defer func() { config.GetConfig().AuthorColumnWidth = oldWidth }()
+
+	tests := []struct {
+		name  string
+		width int
+		input string
+		want  string
+	}{
+		{
+			name:  "column disabled",
+			width: 0,
+			input: "name",
+			want:  "name",
+		}, {
+			name:  "short name",
+			width: 6,
+			input: "name",
+			want:  "  name",
+		}, {
+			name:  "name fits exactly",
+			width: 4,
+			input: "name",
+			want:  "name",
+		}, {
+			name:  "name too long",
+			width: 3,
+			input: "name",
+			want:  "na…",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GetConfig().AuthorColumnWidth = tt.width
+			if got := alignToAuthorColumn(tt.input); got != tt.want {
+				t.Errorf("alignToAuthorColumn() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatView_applyMessageTemplate(t *testing.T) {
+	oldTemplate := config.GetConfig().MessageTemplate
+	defer func() { config.GetConfig().MessageTemplate = oldTemplate }()
+
+	values := map[string]string{
+		"nick":    "name",
+		"content": "text",
+	}
+	tests := []struct {
+		name     string
+		template string
+		followUp followUpType
+		want     string
+	}{
+		{
+			name:     "irc style",
+			template: "<{nick}> {content}",
+			want:     "[#ffffff]<name> [#ffffff]text[\"\"][\"\"]",
+		}, {
+			name:     "tags in template are escaped",
+			template: "[red]{nick}",
+			want:     "[#ffffff][red[]name[\"\"][\"\"]",
+		}, {
+			name:     "grouped message",
+			template: "<{nick}> {content}",
+			followUp: groupedFollowUp,
+			want:     "       [#ffffff]text[\"\"][\"\"]",
+		}, {
+			name:     "unknown placeholders are empty",
+			template: "{unknown}{nick}",
+			want:     "[#ffffff]name[\"\"][\"\"]",
+		},
+	}
+	chatView := &ChatView{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GetConfig().MessageTemplate = tt.template
+			if got := chatView.applyMessageTemplate(formattingDetails{followUp: tt.followUp}, values); got != tt.want {
+				t.Errorf("ChatView.applyMessageTemplate() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formattingDetails_rendersLike(t *testing.T) {
+	now := time.Date(2019, 10, 10, 10, 0, 0, 0, time.UTC)
+	minutesAgo := func(minutes time.Duration) string {
+		return times.TimeToRelativeString(now.Add(-minutes*time.Minute), now)
+	}
+	tests := []struct {
+		name    string
+		details formattingDetails
+		other   formattingDetails
+		want    bool
+	}{
+		{
+			name:    "same time",
+			details: formattingDetails{followUp: noFollowUp, timeText: minutesAgo(2)},
+			other:   formattingDetails{followUp: noFollowUp, timeText: minutesAgo(2)},
+			want:    true,
+		}, {
+			name:    "displayed time changed",
+			details: formattingDetails{followUp: noFollowUp, timeText: minutesAgo(2)},
+			other:   formattingDetails{followUp: noFollowUp, timeText: minutesAgo(3)},
+			want:    false,
+		}, {
+			name:    "hidden time changed",
+			details: formattingDetails{followUp: compactFollowUp, timeText: minutesAgo(2)},
+			other:   formattingDetails{followUp: compactFollowUp, timeText: minutesAgo(3)},
+			want:    true,
+		}, {
+			name:    "hidden time changed width",
+			details: formattingDetails{followUp: groupedFollowUp, timeText: minutesAgo(9)},
+			other:   formattingDetails{followUp: groupedFollowUp, timeText: minutesAgo(10)},
+			want:    false,
+		}, {
+			name:    "follow up changed",
+			details: formattingDetails{followUp: noFollowUp, timeText: minutesAgo(2)},
+			other:   formattingDetails{followUp: compactFollowUp, timeText: minutesAgo(2)},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.details.rendersLike(tt.other); got != tt.want {
+				t.Errorf("formattingDetails.rendersLike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatView_getFollowUpType(t *testing.T) {
+	oldCompactMessages := config.GetConfig().CompactMessages
+	oldGroupingWindow := config.GetConfig().MessageGroupingWindow
+	defer func() {
+		config.GetConfig().CompactMessages = oldCompactMessages
+		config.GetConfig().MessageGroupingWindow = oldGroupingWindow
+	}()
+
+	messages := []*discordgo.Message{
+		{ID: "1", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:00:00+00:00"},
+		{ID: "2", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:01:00+00:00"},
+		{ID: "3", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:30:00+00:00"},
+		{ID: "4", Author: &discordgo.User{ID: "2"}, Timestamp: "2019-10-10T10:31:00+00:00"},
+		{ID: "5", Author: &discordgo.User{ID: "2"}, Timestamp: "2019-10-12T10:31:00+00:00"},
+	}
+	tests := []struct {
+		name            string
+		compactMessages bool
+		groupingWindow  int
+		want            []followUpType
+	}{
+		{
+			name:            "everything disabled",
+			compactMessages: false,
+			groupingWindow:  0,
+			want:            []followUpType{noFollowUp, noFollowUp, noFollowUp, noFollowUp, noFollowUp},
+		}, {
+			name:            "compact messages",
+			compactMessages: true,
+			groupingWindow:  0,
+			want:            []followUpType{noFollowUp, compactFollowUp, compactFollowUp, noFollowUp, noFollowUp},
+		}, {
+			name:            "grouping",
+			compactMessages: false,
+			groupingWindow:  300,
+			want:            []followUpType{noFollowUp, groupedFollowUp, noFollowUp, noFollowUp, noFollowUp},
+		}, {
+			name:            "grouping and compact messages",
+			compactMessages: true,
+			groupingWindow:  300,
+			want:            []followUpType{noFollowUp, groupedFollowUp, compactFollowUp, noFollowUp, noFollowUp},
+		},
+	}
+	chatView := &ChatView{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GetConfig().CompactMessages = tt.compactMessages
+			config.GetConfig().MessageGroupingWindow = tt.groupingWindow
+			for index, want := range tt.want {
+				if got := chatView.getFollowUpType(messages, index); got != want {
+					t.Errorf("ChatView.getFollowUpType(%d) = %v, want %v", index, got, want)
+				}
+			}
+			if got := chatView.getFollowUpTypes(messages); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChatView.getFollowUpTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatView_getFollowUpType_groupSpan(t *testing.T) {
+	oldCompactMessages := config.GetConfig().CompactMessages
+	oldGroupingWindow := config.GetConfig().MessageGroupingWindow
+	defer func() {
+		config.GetConfig().CompactMessages = oldCompactMessages
+		config.GetConfig().MessageGroupingWindow = oldGroupingWindow
+	}()
+
+	config.GetConfig().CompactMessages = true
+	config.GetConfig().MessageGroupingWindow = 60
+
+	//Every gap is within the window, but the whole group isn't.
+	messages := []*discordgo.Message{
+		{ID: "1", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:00:00+00:00"},
+		{ID: "2", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:00:40+00:00"},
+		{ID: "3", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:01:20+00:00"},
+		{ID: "4", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:01:50+00:00"},
+	}
+	want := []followUpType{noFollowUp, groupedFollowUp, compactFollowUp, groupedFollowUp}
+
+	chatView := &ChatView{}
+	for index, want := range want {
+		if got := chatView.getFollowUpType(messages, index); got != want {
+			t.Errorf("ChatView.getFollowUpType(%d) = %v, want %v", index, got, want)
+		}
+	}
+	if got := chatView.getFollowUpTypes(messages); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChatView.getFollowUpTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestChatView_DeleteMessages_groupStart(t *testing.T) {
+	oldCompactMessages := config.GetConfig().CompactMessages
+	oldGroupingWindow := config.GetConfig().MessageGroupingWindow
+	defer func() {
+		config.GetConfig().CompactMessages = oldCompactMessages
+		config.GetConfig().MessageGroupingWindow = oldGroupingWindow
+	}()
+
+	config.GetConfig().CompactMessages = true
+	config.GetConfig().MessageGroupingWindow = 60
+
+	chatView := createTestChatView(0)
+	chatView.SetMessages([]*discordgo.Message{
+		{ID: "1", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:00:00+00:00", Content: "a"},
+		{ID: "2", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:00:40+00:00", Content: "b"},
+		{ID: "3", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:01:20+00:00", Content: "c"},
+		{ID: "4", Author: &discordgo.User{ID: "1"}, Timestamp: "2019-10-10T10:01:50+00:00", Content: "d"},
+	})
+
+	//The group now starts at the second message, changing the follow-ups of
+	//all remaining messages.
+	chatView.DeleteMessages([]string{"1"})
+	cached := chatView.internalTextView.GetText(false)
+	chatView.Rerender()
+	if full := chatView.internalTextView.GetText(false); cached != full {
+		t.Errorf("cached text differs from full rerender:\n%v\n----\n%v", cached, full)
+	}
+}
+
+func Test_highlightCode(t *testing.T) {
+	oldFormatter := config.GetTheme().SyntaxHighlightingFormatter
+	defer func() { config.GetTheme().SyntaxHighlightingFormatter = oldFormatter }()
+
+	//The test manipulates the cache, so it works on a copy of its own.
+	highlightedCodeCacheMutex.Lock()
+	oldCache := highlightedCodeCache
+	highlightedCodeCache = make(map[highlightedCodeCacheKey]string)
+	highlightedCodeCacheMutex.Unlock()
+	defer func() {
+		highlightedCodeCacheMutex.Lock()
+		highlightedCodeCache = oldCache
+		highlightedCodeCacheMutex.Unlock()
+	}()
+
+	config.GetTheme().SyntaxHighlightingFormatter = "tview-8bit"
+	indexed := highlightCode("go", "one")
+	if indexed != "[#c9dddc]▐ [#efef8b]one" {
+		t.Errorf("highlightCode() = '%v', want '%v'", indexed, "[#c9dddc]▐ [#efef8b]one")
+	}
+
+	config.GetTheme().SyntaxHighlightingFormatter = "tview-truecolor"
+	trueColor := highlightCode("go", "one")
+	if trueColor != "[#c9dddc]▐ [#a6e22e]one" {
+		t.Errorf("highlightCode() = '%v', want '%v'", trueColor, "[#c9dddc]▐ [#a6e22e]one")
+	}
+
+	//Changing the cached value proves that the cache is being used.
+	highlightedCodeCacheMutex.Lock()
+	for key := range highlightedCodeCache {
+		if key.formatter == "tview-truecolor" && key.codeHash == sha256.Sum256([]byte("one")) {
+			highlightedCodeCache[key] = "cached"
+		}
+	}
+	highlightedCodeCacheMutex.Unlock()
+
+	if cached := highlightCode("go", "one"); cached != "cached" {
+		t.Errorf("highlightCode() = '%v', but expected the cached value", cached)
+	}
+}
+
+func createTestChatView(messageCount int) *ChatView {
+	chatView := NewChatView(discordgo.NewState(), "0")
+	chatView.internalTextView.SetRect(0, 0, 120, 40)
+
+	startTime := time.Date(2019, 10, 10, 10, 0, 0, 0, time.UTC)
+	messages := make([]*discordgo.Message, 0, messageCount)
+	for index := 0; index < messageCount; index++ {
+		messages = append(messages, &discordgo.Message{
+			ID:        strconv.Itoa(index + 1),
+			Type:      discordgo.MessageTypeDefault,
+			Author:    &discordgo.User{ID: strconv.Itoa(index % 3), Username: "user" + strconv.Itoa(index%3)},
+			Timestamp: discordgo.Timestamp(startTime.Add(time.Duration(index) * time.Hour).Format(time.RFC3339)),
+			Content:   "**Hello** _there_, this is ||message|| number " + strconv.Itoa(index) + " with `code` in it.",
+		})
+	}
+	chatView.SetMessages(messages)
+
+	return chatView
+}
+
+func TestChatView_cachedRendering(t *testing.T) {
+	chatView := createTestChatView(50)
+	assertSameAsFullRerender := func(action string) {
+		cached := chatView.internalTextView.GetText(false)
+		chatView.Rerender()
+		if full := chatView.internalTextView.GetText(false); cached != full {
+			t.Errorf("%s: cached text differs from full rerender:\n%v\n----\n%v", action, cached, full)
+		}
+	}
+
+	assertSameAsFullRerender("SetMessages")
+
+	message := chatView.data[10]
+	message.Content = "edited"
+	chatView.UpdateMessage(message)
+	assertSameAsFullRerender("UpdateMessage")
+
+	//Deleting the first message of a day requires the next message to
+	//show the date delimiter instead.
+	chatView.DeleteMessages([]string{chatView.data[0].ID, chatView.data[24].ID, chatView.data[25].ID})
+	assertSameAsFullRerender("DeleteMessages")
+
+	chatView.AddMessage(&discordgo.Message{
+		ID:        "1000",
+		Author:    &discordgo.User{ID: "1"},
+		Timestamp: "2019-10-20T10:00:00+00:00",
+		Content:   "new",
+	})
+	assertSameAsFullRerender("AddMessage")
+
+	chatView.PrependMessages([]*discordgo.Message{{
+		ID:        "0",
+		Author:    &discordgo.User{ID: "1"},
+		Timestamp: "2019-10-01T10:00:00+00:00",
+		Content:   "old",
+	}})
+	assertSameAsFullRerender("PrependMessages")
+
+	if len(chatView.renderedMessages) != len(chatView.data) {
+		t.Errorf("%d rendered messages, but %d messages", len(chatView.renderedMessages), len(chatView.data))
+	}
+}
+
+func TestChatView_deleteSelectedMessage(t *testing.T) {
+	oldBehaviour := config.GetConfig().DeletedMessageBehaviour
+	defer func() {
+		config.GetConfig().DeletedMessageBehaviour = oldBehaviour
+	}()
+
+	for _, behaviour := range []int{config.RemoveDeletedMessages, config.StrikeThroughDeletedMessages} {
+		config.GetConfig().DeletedMessageBehaviour = behaviour
+		chatView := createTestChatView(10)
+		chatView.selection = 5
+		selectedMessage := chatView.data[5]
+
+		//Deleting other messages keeps the selection on the same message.
+		chatView.DeleteMessages([]string{chatView.data[1].ID})
+		if chatView.selection < 0 || chatView.data[chatView.selection] != selectedMessage {
+			t.Errorf("behaviour %d: selection %d doesn't point to the selected message anymore", behaviour, chatView.selection)
+		}
+
+		chatView.DeleteMessage(selectedMessage)
+		if chatView.selection != -1 {
+			t.Errorf("behaviour %d: selection = %d after deleting the selected message, want -1", behaviour, chatView.selection)
+		}
+		if highlights := chatView.internalTextView.GetHighlights(); len(highlights) != 0 {
+			t.Errorf("behaviour %d: highlights = %v after deleting the selected message, want none", behaviour, highlights)
+		}
+	}
+}
+
+func TestChatView_PrependMessages_blocked(t *testing.T) {
+	oldShowPlaceholder := config.GetConfig().ShowPlaceholderForBlockedMessages
+	defer func() {
+		config.GetConfig().ShowPlaceholderForBlockedMessages = oldShowPlaceholder
+	}()
+	config.GetConfig().ShowPlaceholderForBlockedMessages = false
+
+	chatView := createTestChatView(10)
+	chatView.state.Relationships = []*discordgo.Relationship{
+		{User: &discordgo.User{ID: "blocked"}, Type: discordgo.RelationTypeBlocked},
+	}
+	if chatView.oldestMessageID != "1" {
+		t.Errorf("oldestMessageID = %s, want 1", chatView.oldestMessageID)
+	}
+
+	//A page that only contains blocked messages doesn't show anything, but
+	//the next page has to be requested before it nonetheless.
+	chatView.PrependMessages([]*discordgo.Message{
+		{ID: "-2", Author: &discordgo.User{ID: "blocked"}, Timestamp: "2019-10-01T10:00:00+00:00"},
+		{ID: "-1", Author: &discordgo.User{ID: "blocked"}, Timestamp: "2019-10-01T11:00:00+00:00"},
+	})
+	if len(chatView.data) != 10 {
+		t.Errorf("%d messages, want the blocked messages to be hidden", len(chatView.data))
+	}
+	if chatView.oldestMessageID != "-2" {
+		t.Errorf("oldestMessageID = %s, want -2", chatView.oldestMessageID)
+	}
+}
+
+func TestChatView_unreadMarkerAfterLiveMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		authorID string
+	}{
+		{name: "message of someone else", authorID: "1"},
+		{name: "own message", authorID: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatView := createTestChatView(0)
+			messages := createTestChatView(10).data
+			//The channel has been read completely.
+			chatView.SetUnreadMarker(messages[len(messages)-1].ID)
+			chatView.SetMessages(messages)
+
+			chatView.AddMessage(&discordgo.Message{
+				ID:        "1000",
+				Author:    &discordgo.User{ID: tt.authorID},
+				Timestamp: messages[len(messages)-1].Timestamp,
+				Content:   "live",
+			})
+			if strings.Contains(chatView.internalTextView.GetText(false), "new messages") {
+				t.Error("live message is preceded by an unread marker")
+			}
+		})
+	}
+}
+
+func TestChatView_keepDeletedMessages(t *testing.T) {
+	oldBehaviour := config.GetConfig().DeletedMessageBehaviour
+	defer func() {
+		config.GetConfig().DeletedMessageBehaviour = oldBehaviour
+	}()
+
+	deletedMarker := "[" + tviewutil.ColorToHex(config.GetTheme().ErrorColor) + "](deleted)[#ffffff]"
+	tests := []struct {
+		name      string
+		behaviour int
+		content   string
+		want      string
+	}{
+		{
+			name:      "strike through",
+			behaviour: config.StrikeThroughDeletedMessages,
+			content:   "**a** b",
+			want:      "[::b]" + strikeThrough("a") + "[::-]" + strikeThrough(" b") + " " + deletedMarker,
+		}, {
+			name:      "dim",
+			behaviour: config.DimDeletedMessages,
+			content:   "**a** b",
+			want:      "[::d][::db]a[::d] b[::-] " + deletedMarker,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GetConfig().DeletedMessageBehaviour = tt.behaviour
+			chatView := createTestChatView(10)
+			chatView.data[5].Content = tt.content
+			chatView.UpdateMessage(chatView.data[5])
+
+			chatView.DeleteMessages([]string{chatView.data[5].ID, chatView.data[6].ID})
+			if len(chatView.data) != 10 {
+				t.Errorf("%d messages left, want all 10 to be kept", len(chatView.data))
+			}
+			if got := chatView.formatMessageText(chatView.data[5]); got != tt.want {
+				t.Errorf("ChatView.formatMessageText() = '%v', want: '%v'", got, tt.want)
+			}
+
+			cached := chatView.internalTextView.GetText(false)
+			chatView.Rerender()
+			if full := chatView.internalTextView.GetText(false); cached != full {
+				t.Errorf("cached text differs from full rerender:\n%v\n----\n%v", cached, full)
+			}
+
+			chatView.SetMessages(chatView.data)
+			if strings.Contains(chatView.formatMessageText(chatView.data[5]), "(deleted)") {
+				t.Error("deleted marker is still shown after reloading the messages")
+			}
+		})
+	}
+}
+
+// previousRerender is a copy of Rerender as it was before the rendered
+// messages were cached, including the string concatenation. It serves as
+// the baseline for the benchmarks, since UpdateMessage, DeleteMessages and
+// AddMessage used to call it after every change.
+func previousRerender(chatView *ChatView) {
+	chatView.internalTextView.SetText("")
+	var newContent string
+	for index := range chatView.data {
+		formattedMessage := chatView.getOrFormatMessage(chatView.data[index], chatView.getFollowUpType(chatView.data, index))
+		newContent += chatView.ReturnDateDelimiter(chatView.data, index)
+		newContent += chatView.ReturnUnreadDelimiter(chatView.data, index)
+		newContent = newContent + "\n[\"" + strconv.Itoa(index) + "\"]" + chatView.applyFindHighlights(index, formattedMessage)
+	}
+	fmt.Fprint(chatView.internalTextView, newContent)
+}
+
+// BenchmarkChatView_UpdateMessage compares updating a single message to the
+// previous implementation, which rendered all messages again. Active find
+// queries make rendering more expensive, since all matches are highlighted.
+func BenchmarkChatView_UpdateMessage(b *testing.B) {
+	for _, variant := range []struct {
+		name      string
+		findQuery string
+	}{
+		{name: "", findQuery: ""},
+		{name: " with find query", findQuery: "message"},
+	} {
+		findQuery := variant.findQuery
+		b.Run("cached"+variant.name, func(b *testing.B) {
+			chatView := createTestChatView(500)
+			chatView.setFindQuery(findQuery)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				chatView.UpdateMessage(chatView.data[250])
+			}
+		})
+		b.Run("previous"+variant.name, func(b *testing.B) {
+			chatView := createTestChatView(500)
+			chatView.setFindQuery(findQuery)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				delete(chatView.formattedMessages, chatView.data[250].ID)
+				previousRerender(chatView)
+			}
+		})
+	}
+}
+
+func BenchmarkChatView_DeleteMessages(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		chatView := createTestChatView(500)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if len(chatView.data) < 250 {
+				b.StopTimer()
+				chatView = createTestChatView(500)
+				b.StartTimer()
+			}
+			chatView.DeleteMessages([]string{chatView.data[100].ID})
+		}
+	})
+	b.Run("previous", func(b *testing.B) {
+		chatView := createTestChatView(500)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if len(chatView.data) < 250 {
+				b.StopTimer()
+				chatView = createTestChatView(500)
+				b.StartTimer()
+			}
+			deletedMessageID := chatView.data[100].ID
+			delete(chatView.showSpoilerContent, deletedMessageID)
+			delete(chatView.formattedMessages, deletedMessageID)
+			delete(chatView.formattingDetails, deletedMessageID)
+			filteredMessages := make([]*discordgo.Message, 0)
+			for _, message := range chatView.data {
+				if message.ID != deletedMessageID {
+					filteredMessages = append(filteredMessages, message)
+				}
+			}
+			chatView.data = filteredMessages
+			previousRerender(chatView)
+		}
+	})
+}
+
+func BenchmarkChatView_AddMessage(b *testing.B) {
+	newMessage := func(n int) *discordgo.Message {
+		return &discordgo.Message{
+			ID:        strconv.Itoa(10000 + n),
+			Author:    &discordgo.User{ID: "1"},
+			Timestamp: "2019-11-30T10:00:00+00:00",
+			Content:   "new message",
+		}
+	}
+	b.Run("cached", func(b *testing.B) {
+		chatView := createTestChatView(500)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			chatView.AddMessage(newMessage(n))
+		}
+	})
+	b.Run("previous", func(b *testing.B) {
+		chatView := createTestChatView(500)
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			//The buffer is full, so the first message is dropped.
+			idToDrop := chatView.data[0].ID
+			delete(chatView.showSpoilerContent, idToDrop)
+			delete(chatView.formattedMessages, idToDrop)
+			delete(chatView.formattingDetails, idToDrop)
+			chatView.data = append(chatView.data[1:], newMessage(n))
+			previousRerender(chatView)
+		}
+	})
+}
+
+func BenchmarkChatView_toggleSpoiler(b *testing.B) {
+	chatView := createTestChatView(500)
+	chatView.selectionMode = true
+	chatView.selection = 250
+	handler := chatView.internalTextView.GetInputCapture()
+	event := tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		handler(event)
+	}
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/Bios-Marcel/tview"
+)
+
+var (
+	wordOrSpaceRegex = regexp.MustCompile(`\s+|\S+`)
+)
+
+// messageVersion is the content that a message had at some point.
+type messageVersion struct {
+	content string
+	// timestamp is the time at which this version was created. This is
+	// either the time the message was sent or the time of an edit.
+	timestamp discordgo.Timestamp
+}
+
+// editHistory remembers the previous versions of all messages that have been
+// edited while the application is running.
+type editHistory struct {
+	mutex    *sync.Mutex
+	versions map[string][]*messageVersion
+}
+
+func newEditHistory() *editHistory {
+	return &editHistory{
+		mutex:    &sync.Mutex{},
+		versions: make(map[string][]*messageVersion),
+	}
+}
+
+// recordEdit remembers the content of the message before the edit. Updates
+// that don't change the content, for example embeds being added to a
+// message, are ignored.
+func (history *editHistory) recordEdit(previous, edited *discordgo.Message) {
+	if edited.EditedTimestamp == "" || previous.Content == edited.Content {
+		return
+	}
+
+	timestamp := previous.EditedTimestamp
+	if timestamp == "" {
+		timestamp = previous.Timestamp
+	}
+
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	history.versions[previous.ID] = append(history.versions[previous.ID], &messageVersion{
+		content:   previous.Content,
+		timestamp: timestamp,
+	})
+}
+
+// getVersions returns all known versions of the message, starting with the
+// oldest one and ending with the current one. If no edit has been recorded,
+// nil is returned.
+func (history *editHistory) getVersions(message *discordgo.Message) []*messageVersion {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	previousVersions := history.versions[message.ID]
+	if len(previousVersions) == 0 {
+		return nil
+	}
+
+	versions := make([]*messageVersion, 0, len(previousVersions)+1)
+	versions = append(versions, previousVersions...)
+	return append(versions, &messageVersion{
+		content:   message.Content,
+		timestamp: message.EditedTimestamp,
+	})
+}
+
+// diffWords compares the two texts word by word and returns the new text,
+// including the removed words, using tview tags. Removed words are struck
+// through and added words are highlighted.
+func diffWords(oldText, newText string) string {
+	oldWords := wordOrSpaceRegex.FindAllString(oldText, -1)
+	newWords := wordOrSpaceRegex.FindAllString(newText, -1)
+
+	//commonLength[i][j] is the length of the longest common subsequence of
+	//oldWords[i:] and newWords[j:].
+	commonLength := make([][]int, len(oldWords)+1)
+	for i := range commonLength {
+		commonLength[i] = make([]int, len(newWords)+1)
+	}
+	for i := len(oldWords) - 1; i >= 0; i-- {
+		for j := len(newWords) - 1; j >= 0; j-- {
+			if oldWords[i] == newWords[j] {
+				commonLength[i][j] = commonLength[i+1][j+1] + 1
+			} else if commonLength[i+1][j] >= commonLength[i][j+1] {
+				commonLength[i][j] = commonLength[i+1][j]
+			} else {
+				commonLength[i][j] = commonLength[i][j+1]
+			}
+		}
+	}
+
+	var builder strings.Builder
+	var removed, added strings.Builder
+	flushChanges := func() {
+		if removed.Len() > 0 {
+			builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().DiffRemovedColor) + "]" +
+				strikeThrough(tview.Escape(removed.String())) +
+				"[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]")
+			removed.Reset()
+		}
+		if added.Len() > 0 {
+			builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().DiffAddedColor) + "]" +
+				tview.Escape(added.String()) +
+				"[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]")
+			added.Reset()
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(oldWords) || j < len(newWords) {
+		if i < len(oldWords) && j < len(newWords) && oldWords[i] == newWords[j] {
+			flushChanges()
+			builder.WriteString(tview.Escape(oldWords[i]))
+			i++
+			j++
+		} else if j == len(newWords) || (i < len(oldWords) && commonLength[i+1][j] >= commonLength[i][j+1]) {
+			removed.WriteString(oldWords[i])
+			i++
+		} else {
+			added.WriteString(newWords[j])
+			j++
+		}
+	}
+	flushChanges()
+
+	return builder.String()
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func Test_diffWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "unchanged",
+			oldText: "Hello World",
+			newText: "Hello World",
+			want:    "Hello World",
+		}, {
+			name:    "added word",
+			oldText: "Hello World",
+			newText: "Hello beautiful World",
+			want:    "Hello [#008000]beautiful [#ffffff]World",
+		}, {
+			name:    "removed word",
+			oldText: "Hello beautiful World",
+			newText: "Hello World",
+			want:    "Hello [#ff0000]b̶e̶a̶u̶t̶i̶f̶u̶l̶ ̶[#ffffff]World",
+		}, {
+			name:    "replaced word",
+			oldText: "Hello World",
+			newText: "Hello Welt",
+			want:    "Hello [#ff0000]W̶o̶r̶l̶d̶[#ffffff][#008000]Welt[#ffffff]",
+		}, {
+			name:    "tags are escaped",
+			oldText: "[red]",
+			newText: "[red] [blue]",
+			want:    "[red[][#008000] [blue[][#ffffff]",
+		}, {
+			name:    "from empty",
+			oldText: "",
+			newText: "new",
+			want:    "[#008000]new[#ffffff]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffWords(tt.oldText, tt.newText); got != tt.want {
+				t.Errorf("diffWords() = '%v', want '%v'", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_editHistory(t *testing.T) {
+	history := newEditHistory()
+	original := &discordgo.Message{ID: "1", Content: "a", Timestamp: "2019-10-10T10:00:00+00:00"}
+	if versions := history.getVersions(original); versions != nil {
+		t.Errorf("getVersions() = %v, want nil for unedited message", versions)
+	}
+
+	//Embeds being added don't count as edits.
+	history.recordEdit(original, &discordgo.Message{ID: "1", Content: "a"})
+	if versions := history.getVersions(original); versions != nil {
+		t.Errorf("getVersions() = %v, want nil for embed update", versions)
+	}
+
+	firstEdit := &discordgo.Message{ID: "1", Content: "b", Timestamp: original.Timestamp, EditedTimestamp: "2019-10-10T10:01:00+00:00"}
+	history.recordEdit(original, firstEdit)
+	secondEdit := &discordgo.Message{ID: "1", Content: "c", Timestamp: original.Timestamp, EditedTimestamp: "2019-10-10T10:02:00+00:00"}
+	history.recordEdit(firstEdit, secondEdit)
+
+	versions := history.getVersions(secondEdit)
+	want := []messageVersion{
+		{content: "a", timestamp: original.Timestamp},
+		{content: "b", timestamp: firstEdit.EditedTimestamp},
+		{content: "c", timestamp: secondEdit.EditedTimestamp},
+	}
+	if len(versions) != len(want) {
+		t.Fatalf("getVersions() returned %d versions, want %d", len(versions), len(want))
+	}
+	for index, version := range versions {
+		if *version != want[index] {
+			t.Errorf("version %d = %v, want %v", index, *version, want[index])
+		}
+	}
+}
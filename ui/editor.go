@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Bios-Marcel/cordless/shortcuts"
 	"github.com/Bios-Marcel/cordless/ui/tviewutil"
@@ -40,6 +41,11 @@ type Editor struct {
 	requestedHeight          int
 	currentMentionBeginIndex int
 	currentMentionEndIndex   int
+
+	typingHandler func()
+	// lastTypingNotification is the last time the typingHandler has been
+	// called.
+	lastTypingNotification time.Time
 }
 
 func (e *Editor) ExpandSelectionToLeft(left, right, selection []rune) {
@@ -353,13 +359,16 @@ func NewEditor() *Editor {
 			return nil
 		} else if shortcuts.PasteAtSelection.Equals(event) {
 			editor.Paste(left, right, selection, event)
+			editor.notifyTyping()
 			return nil
 		} else if shortcuts.InputNewLine.Equals(event) {
 			editor.InsertCharacter(left, right, selection, '\n')
+			editor.notifyTyping()
 		} else if shortcuts.SendMessage.Equals(event) {
 			return editor.inputCapture(event)
 		} else if (editor.inputCapture == nil || editor.inputCapture(event) != nil) && event.Rune() != 0 {
 			editor.InsertCharacter(left, right, selection, event.Rune())
+			editor.notifyTyping()
 		} else {
 			return event
 		}
@@ -470,6 +479,9 @@ func (editor *Editor) SetBackgroundColor(color tcell.Color) {
 // SetText sets the texts of the internal TextView, but also sets the selection
 // and necessary groups for the navigation behaviour.
 func (editor *Editor) SetText(text string) {
+	//Sending a message ends the typing notification, therefore the next
+	//character typed has to trigger a new one.
+	editor.lastTypingNotification = time.Time{}
 	if text == "" {
 		editor.internalTextView.SetText(emptyText)
 	} else {
@@ -479,6 +491,26 @@ func (editor *Editor) SetText(text string) {
 	editor.triggerHeightRequestIfNeccessary()
 }
 
+// SetTypingHandler sets the handler that is called when the user types. The
+// handler is called at most once per typingNotificationInterval.
+func (editor *Editor) SetTypingHandler(handler func()) {
+	editor.typingHandler = handler
+}
+
+func (editor *Editor) notifyTyping() {
+	if editor.typingHandler == nil {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(editor.lastTypingNotification) < typingNotificationInterval {
+		return
+	}
+
+	editor.lastTypingNotification = now
+	editor.typingHandler()
+}
+
 // SetBorderFocusColor delegates to the underlying components
 // SetBorderFocusColor method.
 func (editor *Editor) SetBorderFocusColor(color tcell.Color) {
@@ -1,7 +1,12 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/readstate"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
 	"github.com/Bios-Marcel/discordgo"
 	"github.com/Bios-Marcel/tview"
 )
@@ -11,12 +16,23 @@ import (
 type GuildList struct {
 	*tview.TreeView
 	onGuildSelect func(node *tview.TreeNode, guildID string)
+
+	// folders are the folders last passed to SetFolders.
+	folders []*discordutil.GuildFolder
+	// folderNodes maps the IDs of guilds to the folder node that contains
+	// them. Guilds that aren't part of any folder aren't contained.
+	folderNodes map[string]*tview.TreeNode
+	// guildNames maps the IDs of guilds to their unescaped names, since the
+	// node texts also contain the badges.
+	guildNames map[string]string
 }
 
 // NewGuildList creates and initializes a ready to use GuildList.
 func NewGuildList(guilds []*discordgo.Guild, window *Window) *GuildList {
 	guildList := &GuildList{
-		TreeView: tview.NewTreeView(),
+		TreeView:    tview.NewTreeView(),
+		folderNodes: make(map[string]*tview.TreeNode),
+		guildNames:  make(map[string]string),
 	}
 
 	guildList.
@@ -29,6 +45,11 @@ func NewGuildList(guilds []*discordgo.Guild, window *Window) *GuildList {
 	root := tview.NewTreeNode("")
 	guildList.SetRoot(root)
 	guildList.SetSelectedFunc(func(node *tview.TreeNode) {
+		if _, isFolder := node.GetReference().(*discordutil.GuildFolder); isFolder {
+			node.SetExpanded(!node.IsExpanded())
+			return
+		}
+
 		guildID, ok := node.GetReference().(string)
 		if ok && guildList.onGuildSelect != nil {
 			guildList.onGuildSelect(node, guildID)
@@ -44,6 +65,8 @@ func NewGuildList(guilds []*discordgo.Guild, window *Window) *GuildList {
 		guildNode := tview.NewTreeNode(tview.Escape(guild.Name))
 		guildNode.SetReference(guild.ID)
 		root.AddChild(guildNode)
+		guildList.guildNames[guild.ID] = guild.Name
+		guildList.UpdateGuildBadge(guild.ID)
 
 		window.updateServerReadStatus(guild.ID, guildNode, false)
 
@@ -62,36 +85,202 @@ func (g *GuildList) SetOnGuildSelect(handler func(node *tview.TreeNode, guildID
 	g.onGuildSelect = handler
 }
 
-// RemoveGuild removes the node that refers to the given guildID.
+// GetGuildNode returns the node that refers to the given guildID. If
+// there's no such node, nil is returned.
+func (g *GuildList) GetGuildNode(guildID string) *tview.TreeNode {
+	return tviewutil.FindNodeByReference(g.GetRoot(), guildID)
+}
+
+// SetFolders groups the guilds into the given folders. The guilds are
+// ordered the same way as the folders. Guilds that aren't part of any of
+// the folders are put at the end. Folders are collapsed, unless they were
+// expanded before or contain the selected guild.
+func (g *GuildList) SetFolders(folders []*discordutil.GuildFolder) {
+	guildNodes := make(map[string]*tview.TreeNode)
+	var unsortedGuildIDs []string
+	expandedFolders := make(map[int64]bool)
+	g.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		switch reference := node.GetReference().(type) {
+		case string:
+			guildNodes[reference] = node
+			unsortedGuildIDs = append(unsortedGuildIDs, reference)
+		case *discordutil.GuildFolder:
+			expandedFolders[*reference.ID] = node.IsExpanded()
+		}
+		return true
+	})
+
+	currentNode := g.GetCurrentNode()
+	g.folders = folders
+	g.folderNodes = make(map[string]*tview.TreeNode)
+	var newChildren []*tview.TreeNode
+	for _, folder := range folders {
+		var folderChildren []*tview.TreeNode
+		for _, guildID := range folder.GuildIDs {
+			guildNode, exists := guildNodes[guildID]
+			if exists {
+				folderChildren = append(folderChildren, guildNode)
+				delete(guildNodes, guildID)
+			}
+		}
+
+		if len(folderChildren) == 0 {
+			continue
+		}
+
+		if !folder.IsFolder() {
+			newChildren = append(newChildren, folderChildren...)
+			continue
+		}
+
+		folderNode := tview.NewTreeNode(g.formatFolderName(folder, folderChildren))
+		folderNode.SetReference(folder)
+		folderNode.SetChildren(folderChildren)
+		folderNode.SetExpanded(expandedFolders[*folder.ID])
+		for _, guildNode := range folderChildren {
+			g.folderNodes[guildNode.GetReference().(string)] = folderNode
+			if guildNode == currentNode {
+				folderNode.SetExpanded(true)
+			}
+		}
+		newChildren = append(newChildren, folderNode)
+		g.UpdateFolderReadStatus(folderChildren[0].GetReference().(string))
+	}
+
+	for _, guildID := range unsortedGuildIDs {
+		if guildNode, remaining := guildNodes[guildID]; remaining {
+			newChildren = append(newChildren, guildNode)
+		}
+	}
+
+	g.GetRoot().SetChildren(newChildren)
+}
+
+// formatFolderName returns the text for a folder node. Folders without a
+// name are named after the guilds they contain, just like in the official
+// client. The folders colour is shown in front of the name.
+func (g *GuildList) formatFolderName(folder *discordutil.GuildFolder, guildNodes []*tview.TreeNode) string {
+	name := tview.Escape(folder.Name)
+	if name == "" {
+		guildNames := make([]string, 0, len(guildNodes))
+		for _, guildNode := range guildNodes {
+			guildNames = append(guildNames, tview.Escape(g.guildNames[guildNode.GetReference().(string)]))
+		}
+		name = strings.Join(guildNames, ", ")
+	}
+
+	if folder.Color != nil {
+		return roleColorToTag(*folder.Color) + "■[-] " + name
+	}
+
+	return "■ " + name
+}
+
+// UpdateFolderReadStatus colours the folder that contains the given guild,
+// depending on whether all guilds in the folder have been read. The badge
+// of the folder sums up the badges of its guilds. If the guild isn't part
+// of a folder, nothing happens.
+func (g *GuildList) UpdateFolderReadStatus(guildID string) {
+	folderNode, inFolder := g.folderNodes[guildID]
+	if !inFolder {
+		return
+	}
+
+	folderRead := true
+	var unread, mentions int
+	for _, guildNode := range folderNode.GetChildren() {
+		childGuildID := guildNode.GetReference().(string)
+		if folderRead && !readstate.HasGuildBeenRead(childGuildID) {
+			folderRead = false
+		}
+
+		guildUnread, guildMentions := readstate.GetGuildCounts(childGuildID)
+		unread += guildUnread
+		mentions += guildMentions
+	}
+
+	folder := folderNode.GetReference().(*discordutil.GuildFolder)
+	folderNode.SetText(g.formatFolderName(folder, folderNode.GetChildren()) + formatCountBadge(unread, mentions))
+	if folderRead {
+		folderNode.SetColor(tview.Styles.PrimaryTextColor)
+	} else {
+		folderNode.SetColor(config.GetTheme().AttentionColor)
+	}
+}
+
+// UpdateGuildBadge updates the badge of the given guild, showing the amount
+// of unread messages and mentions in the guild, and whether it's muted.
+func (g *GuildList) UpdateGuildBadge(guildID string) {
+	if node := g.GetGuildNode(guildID); node != nil {
+		node.SetText(withMutedIndicator(tview.Escape(g.guildNames[guildID]), readstate.IsGuildMuted(guildID)) +
+			formatCountBadge(readstate.GetGuildCounts(guildID)))
+	}
+}
+
+// RemoveGuild removes the node that refers to the given guildID. If the
+// guild was the last guild of a folder, the folder is removed as well.
 func (g *GuildList) RemoveGuild(guildID string) {
-	children := g.GetRoot().GetChildren()
-	indexToRemove := -1
-	for index, node := range children {
-		if node.GetReference() == guildID {
-			indexToRemove = index
-			break
+	folderNode, inFolder := g.folderNodes[guildID]
+	delete(g.folderNodes, guildID)
+	delete(g.guildNames, guildID)
+	parent := g.GetRoot()
+	if inFolder {
+		parent = folderNode
+	}
+
+	removeChildNode(parent, guildID)
+	if inFolder {
+		if len(folderNode.GetChildren()) == 0 {
+			removeChildNode(g.GetRoot(), folderNode.GetReference())
+		} else {
+			g.UpdateFolderReadStatus(folderNode.GetChildren()[0].GetReference().(string))
 		}
 	}
+}
 
-	if indexToRemove != -1 {
-		g.GetRoot().SetChildren(append(children[:indexToRemove], children[indexToRemove+1:]...))
+// removeChildNode removes the direct child of the given parent that refers
+// to the given reference.
+func removeChildNode(parent *tview.TreeNode, reference interface{}) {
+	children := parent.GetChildren()
+	for index, node := range children {
+		if node.GetReference() == reference {
+			parent.SetChildren(append(children[:index], children[index+1:]...))
+			break
+		}
 	}
 }
 
 // AddGuild adds a new node that references the given guildID and shows the
-// given name.
+// given name. If the guild is part of a folder, it's added to the folder.
 func (g *GuildList) AddGuild(guildID, name string) {
 	node := tview.NewTreeNode(tview.Escape(name))
 	node.SetReference(guildID)
 	g.GetRoot().AddChild(node)
+	g.guildNames[guildID] = name
+	g.UpdateGuildBadge(guildID)
+
+	if g.folders != nil {
+		g.SetFolders(g.folders)
+	}
 }
 
 // UpdateName updates the name of the guild with the given ID.
 func (g *GuildList) UpdateName(guildID, newName string) {
-	for _, node := range g.GetRoot().GetChildren() {
-		if node.GetReference() == guildID {
-			node.SetText(tview.Escape(newName))
-			break
+	if node := g.GetGuildNode(guildID); node != nil {
+		g.guildNames[guildID] = newName
+		g.UpdateGuildBadge(guildID)
+		//Folders without a name are named after their guilds.
+		if _, inFolder := g.folderNodes[guildID]; inFolder {
+			g.SetFolders(g.folders)
 		}
 	}
 }
+
+// ExpandFolderOf expands the folder that contains the given guild, so that
+// the guild can be selected. If the guild isn't part of a folder, nothing
+// happens.
+func (g *GuildList) ExpandFolderOf(guildID string) {
+	if folderNode, inFolder := g.folderNodes[guildID]; inFolder {
+		folderNode.SetExpanded(true)
+	}
+}
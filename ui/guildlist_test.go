@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/readstate"
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/Bios-Marcel/tview"
+)
+
+func getReferences(nodes []*tview.TreeNode) []interface{} {
+	references := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		references = append(references, node.GetReference())
+	}
+	return references
+}
+
+func TestGuildList_SetFolders(t *testing.T) {
+	state := discordgo.NewState()
+	state.User = &discordgo.User{ID: "U1"}
+	readstate.Load(state)
+
+	guildList := NewGuildList(nil, nil)
+	for _, guildID := range []string{"G1", "G2", "G3", "G4"} {
+		guildList.AddGuild(guildID, guildID)
+	}
+
+	folderID := int64(1)
+	color := 0xff0000
+	folder := &discordutil.GuildFolder{ID: &folderID, Name: "Folder", Color: &color, GuildIDs: []string{"G3", "G1", "G5"}}
+	guildList.SetFolders([]*discordutil.GuildFolder{
+		{GuildIDs: []string{"G2"}},
+		folder,
+	})
+
+	rootChildren := guildList.GetRoot().GetChildren()
+	if references := getReferences(rootChildren); len(references) != 3 ||
+		references[0] != "G2" || references[1] != folder || references[2] != "G4" {
+		t.Fatalf("root children = %v, want [G2 folder G4]", references)
+	}
+	folderNode := rootChildren[1]
+	if references := getReferences(folderNode.GetChildren()); len(references) != 2 ||
+		references[0] != "G3" || references[1] != "G1" {
+		t.Errorf("folder children = %v, want [G3 G1]", references)
+	}
+	if folderNode.IsExpanded() {
+		t.Error("folders should be collapsed initially")
+	}
+	if text := folderNode.GetText(); text != roleColorToTag(color)+"■[-] Folder" {
+		t.Errorf("folder text = %q", text)
+	}
+
+	guildList.ExpandFolderOf("G1")
+	if !folderNode.IsExpanded() {
+		t.Error("folder should have been expanded")
+	}
+
+	//Guilds that arrive later are still put into their folder.
+	guildList.AddGuild("G5", "G5")
+	folderNode = guildList.GetRoot().GetChildren()[1]
+	if references := getReferences(folderNode.GetChildren()); len(references) != 3 || references[2] != "G5" {
+		t.Errorf("folder children = %v, want [G3 G1 G5]", references)
+	}
+	if !folderNode.IsExpanded() {
+		t.Error("folder should have stayed expanded")
+	}
+
+	for _, guildID := range []string{"G1", "G3", "G5"} {
+		guildList.RemoveGuild(guildID)
+	}
+	if references := getReferences(guildList.GetRoot().GetChildren()); len(references) != 2 ||
+		references[0] != "G2" || references[1] != "G4" {
+		t.Errorf("root children = %v, want [G2 G4]", references)
+	}
+}
@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/cordless/util/files"
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
+)
+
+var (
+	linkRegex = regexp.MustCompile(`https?://[^\s<>|]+`)
+
+	// downloadClient is used for downloading links. The timeout covers the
+	// whole download, so that a stalled server can't block it forever.
+	downloadClient = &http.Client{Timeout: 5 * time.Minute}
+)
+
+// messageLink is a link that is part of a message, either as part of the
+// message content or as an attachment.
+type messageLink struct {
+	url string
+	// name is shown in front of the URL if set. Attachments use their
+	// filename as the name.
+	name string
+}
+
+// collectLinks returns all links in the content and attachments of the given
+// messages. Each URL is only contained once, keeping its first occurrence.
+func collectLinks(messages []*discordgo.Message) []*messageLink {
+	var links []*messageLink
+	alreadyAdded := make(map[string]bool)
+	addLink := func(link *messageLink) {
+		if !alreadyAdded[link.url] {
+			alreadyAdded[link.url] = true
+			links = append(links, link)
+		}
+	}
+
+	for _, message := range messages {
+		for _, link := range linkRegex.FindAllString(message.Content, -1) {
+			//Punctuation and markdown at the end are most likely not part of
+			//the link, for example in "Look at https://example.com!".
+			link = strings.TrimRight(link, ".,:;!?'\"()[]*~")
+			addLink(&messageLink{url: link})
+		}
+
+		for _, attachment := range message.Attachments {
+			addLink(&messageLink{url: attachment.URL, name: attachment.Filename})
+		}
+	}
+
+	return links
+}
+
+// LinkPicker is an overlay that lists links, allowing the user to open,
+// copy or download them.
+type LinkPicker struct {
+	*tview.Flex
+
+	app           *tview.Application
+	list          *tview.List
+	downloadInput *tview.InputField
+	description   *tview.TextView
+
+	links []*messageLink
+	// typedNumber contains the digits typed so far, allowing to select
+	// links with multi-digit numbers.
+	typedNumber string
+
+	onOpen     func(url string)
+	onCopy     func(url string)
+	onDownload func(url, directory string)
+	onClose    func()
+}
+
+// NewLinkPicker creates a new picker, numbering all the given links.
+func NewLinkPicker(app *tview.Application, links []*messageLink) *LinkPicker {
+	linkPicker := &LinkPicker{
+		Flex:          tview.NewFlex(),
+		app:           app,
+		list:          tview.NewList(),
+		downloadInput: tview.NewInputField(),
+		description:   tview.NewTextView(),
+		links:         links,
+	}
+
+	linkPicker.list.ShowSecondaryText(false)
+	linkPicker.list.SetBorder(true)
+	linkPicker.list.SetTitle("Links")
+	linkPicker.list.SetMainTextColor(config.GetTheme().PrimaryTextColor)
+	linkPicker.list.SetSelectedTextColor(config.GetTheme().InverseTextColor)
+	linkPicker.list.SetSelectedBackgroundColor(config.GetTheme().PrimaryTextColor)
+	for index, link := range links {
+		linkText := tview.Escape(link.url)
+		if link.name != "" {
+			linkText = tview.Escape(link.name) + " (" + linkText + ")"
+		}
+		linkPicker.list.AddItem(strconv.Itoa(index+1)+". "+linkText, "", 0, nil)
+	}
+	linkPicker.list.SetInputCapture(linkPicker.handleListInput)
+
+	linkPicker.downloadInput.SetLabel("Download to: ")
+	linkPicker.downloadInput.SetInputCapture(linkPicker.handleDownloadInput)
+
+	primitiveBGColor := tviewutil.ColorToHex(config.GetTheme().PrimitiveBackgroundColor)
+	primaryTextColor := tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor)
+	linkPicker.description.SetDynamicColors(true)
+	linkPicker.description.SetText("[" + primaryTextColor + "][:" + primitiveBGColor + "]0-9 [:" + primaryTextColor + "][" + primitiveBGColor + "]Select link" +
+		"[" + primaryTextColor + "][:" + primitiveBGColor + "]  Enter [:" + primaryTextColor + "][" + primitiveBGColor + "]Open" +
+		"[" + primaryTextColor + "][:" + primitiveBGColor + "]  c [:" + primaryTextColor + "][" + primitiveBGColor + "]Copy" +
+		"[" + primaryTextColor + "][:" + primitiveBGColor + "]  d [:" + primaryTextColor + "][" + primitiveBGColor + "]Download" +
+		"[" + primaryTextColor + "][:" + primitiveBGColor + "]  Esc [:" + primaryTextColor + "][" + primitiveBGColor + "]Close")
+
+	linkPicker.SetDirection(tview.FlexRow)
+	linkPicker.AddItem(linkPicker.list, 0, 1, false)
+	linkPicker.AddItem(linkPicker.description, 1, 0, false)
+
+	return linkPicker
+}
+
+// GetFocusTarget returns the component that should be focused when showing
+// the picker.
+func (linkPicker *LinkPicker) GetFocusTarget() tview.Primitive {
+	return linkPicker.list
+}
+
+// SetOnOpen sets the handler that is called when the user wants to open a
+// link.
+func (linkPicker *LinkPicker) SetOnOpen(handler func(url string)) {
+	linkPicker.onOpen = handler
+}
+
+// SetOnCopy sets the handler that is called when the user wants to copy a
+// link.
+func (linkPicker *LinkPicker) SetOnCopy(handler func(url string)) {
+	linkPicker.onCopy = handler
+}
+
+// SetOnDownload sets the handler that is called when the user wants to
+// download a link into the given directory.
+func (linkPicker *LinkPicker) SetOnDownload(handler func(url, directory string)) {
+	linkPicker.onDownload = handler
+}
+
+// SetOnClose sets the handler that is called when the user closes the
+// picker without choosing any action.
+func (linkPicker *LinkPicker) SetOnClose(handler func()) {
+	linkPicker.onClose = handler
+}
+
+func (linkPicker *LinkPicker) currentURL() string {
+	return linkPicker.links[linkPicker.list.GetCurrentItem()].url
+}
+
+func (linkPicker *LinkPicker) handleListInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc {
+		if linkPicker.onClose != nil {
+			linkPicker.onClose()
+		}
+		return nil
+	}
+
+	if event.Key() == tcell.KeyEnter {
+		if linkPicker.onOpen != nil {
+			linkPicker.onOpen(linkPicker.currentURL())
+		}
+		return nil
+	}
+
+	if event.Key() != tcell.KeyRune {
+		linkPicker.typedNumber = ""
+		return event
+	}
+
+	if event.Rune() >= '0' && event.Rune() <= '9' {
+		linkPicker.selectByDigit(event.Rune())
+		return nil
+	}
+	linkPicker.typedNumber = ""
+
+	if event.Rune() == 'c' {
+		if linkPicker.onCopy != nil {
+			linkPicker.onCopy(linkPicker.currentURL())
+		}
+		return nil
+	}
+
+	if event.Rune() == 'd' {
+		linkPicker.downloadInput.SetText(config.GetConfig().DownloadDirectory)
+		linkPicker.RemoveItem(linkPicker.description)
+		linkPicker.AddItem(linkPicker.downloadInput, 1, 0, false)
+		linkPicker.app.SetFocus(linkPicker.downloadInput)
+		return nil
+	}
+
+	return event
+}
+
+// selectByDigit appends the digit to the number typed so far and selects
+// the link with that number. If no link has that number, the digit starts
+// a new number instead.
+func (linkPicker *LinkPicker) selectByDigit(digit rune) {
+	number, _ := strconv.Atoi(linkPicker.typedNumber + string(digit))
+	if number > len(linkPicker.links) {
+		linkPicker.typedNumber = ""
+		number = int(digit - '0')
+	}
+
+	if number < 1 || number > len(linkPicker.links) {
+		linkPicker.typedNumber = ""
+		return
+	}
+
+	linkPicker.typedNumber += string(digit)
+	linkPicker.list.SetCurrentItem(number - 1)
+}
+
+func (linkPicker *LinkPicker) handleDownloadInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc {
+		linkPicker.RemoveItem(linkPicker.downloadInput)
+		linkPicker.AddItem(linkPicker.description, 1, 0, false)
+		linkPicker.app.SetFocus(linkPicker.list)
+		return nil
+	}
+
+	if event.Key() == tcell.KeyEnter {
+		if linkPicker.onDownload != nil {
+			linkPicker.onDownload(linkPicker.currentURL(), linkPicker.downloadInput.GetText())
+		}
+		return nil
+	}
+
+	return event
+}
+
+// openLink opens the given link using the configured LinkOpenCommand.
+func openLink(link string) error {
+	arguments := strings.Fields(config.GetConfig().LinkOpenCommand)
+	if len(arguments) == 0 {
+		return fmt.Errorf("no command for opening links has been configured")
+	}
+
+	containsPlaceholder := false
+	for index, argument := range arguments {
+		if strings.Contains(argument, "{url}") {
+			arguments[index] = strings.Replace(argument, "{url}", link, -1)
+			containsPlaceholder = true
+		}
+	}
+	if !containsPlaceholder {
+		arguments = append(arguments, link)
+	}
+
+	command := exec.Command(arguments[0], arguments[1:]...)
+	startError := command.Start()
+	if startError != nil {
+		return startError
+	}
+
+	//Waiting is required in order to release the processes resources.
+	go command.Wait()
+	return nil
+}
+
+// downloadLink downloads the content of the given link into the given
+// directory. Existing files are never overwritten. The path of the created
+// file is returned.
+func downloadLink(link, directory string) (string, error) {
+	resolvedDirectory, resolveError := files.ToAbsolutePath(directory)
+	if resolveError != nil {
+		return "", resolveError
+	}
+
+	mkdirError := os.MkdirAll(resolvedDirectory, 0755)
+	if mkdirError != nil {
+		return "", mkdirError
+	}
+
+	response, requestError := downloadClient.Get(link)
+	if requestError != nil {
+		return "", requestError
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server responded with '%s'", response.Status)
+	}
+
+	filePath, pathError := findUnusedFilePath(resolvedDirectory, filenameFromLink(link))
+	if pathError != nil {
+		return "", pathError
+	}
+
+	file, createError := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if createError != nil {
+		return "", createError
+	}
+
+	_, copyError := io.Copy(file, response.Body)
+	closeError := file.Close()
+	if copyError != nil {
+		os.Remove(filePath)
+		return "", copyError
+	}
+	if closeError != nil {
+		return "", closeError
+	}
+
+	return filePath, nil
+}
+
+// filenameFromLink uses the last element of the links path as the filename.
+// Both slashes and backslashes are treated as separators, so that the name
+// can't point outside of the download directory. If there's no usable name,
+// a generic filename is returned.
+func filenameFromLink(link string) string {
+	parsedLink, parseError := url.Parse(link)
+	if parseError == nil {
+		filename := filepath.Base(strings.ReplaceAll(parsedLink.Path, "\\", "/"))
+		if filename != "/" && filename != "." && filename != ".." {
+			return filename
+		}
+	}
+
+	return "download"
+}
+
+// findUnusedFilePath returns a path in the given directory that no file
+// exists at yet. If the filename is already taken, a number is added in
+// front of the extension, for example "image (1).png". An error is returned
+// if the filename would lead outside of the directory.
+func findUnusedFilePath(directory, filename string) (string, error) {
+	filePath := filepath.Join(directory, filename)
+	if filepath.Dir(filePath) != filepath.Clean(directory) {
+		return "", fmt.Errorf("filename '%s' leads outside of the directory", filename)
+	}
+
+	extension := filepath.Ext(filename)
+	nameWithoutExtension := strings.TrimSuffix(filename, extension)
+	for counter := 1; ; counter++ {
+		_, statError := os.Stat(filePath)
+		if os.IsNotExist(statError) {
+			return filePath, nil
+		}
+
+		filePath = filepath.Join(directory, fmt.Sprintf("%s (%d)%s", nameWithoutExtension, counter, extension))
+	}
+}
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
+)
+
+func Test_collectLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []*discordgo.Message
+		want     []*messageLink
+	}{
+		{
+			name:     "no links",
+			messages: []*discordgo.Message{{Content: "Hello"}},
+			want:     nil,
+		}, {
+			name:     "trailing punctuation",
+			messages: []*discordgo.Message{{Content: "Look at https://example.com/a_b!"}},
+			want:     []*messageLink{{url: "https://example.com/a_b"}},
+		}, {
+			name:     "embed suppressed and formatted links",
+			messages: []*discordgo.Message{{Content: "<https://a.com> **https://b.com** ||http://c.com||"}},
+			want: []*messageLink{
+				{url: "https://a.com"},
+				{url: "https://b.com"},
+				{url: "http://c.com"},
+			},
+		}, {
+			name: "attachments and duplicates",
+			messages: []*discordgo.Message{
+				{Content: "https://a.com https://a.com"},
+				{
+					Content: "https://a.com",
+					Attachments: []*discordgo.MessageAttachment{
+						{URL: "https://cdn.com/image.png", Filename: "image.png"},
+					},
+				},
+			},
+			want: []*messageLink{
+				{url: "https://a.com"},
+				{url: "https://cdn.com/image.png", name: "image.png"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collectLinks(tt.messages); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collectLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_filenameFromLink(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "file",
+			link: "https://cdn.com/attachments/1/image.png?size=20",
+			want: "image.png",
+		}, {
+			name: "escaped file",
+			link: "https://cdn.com/my%20image.png",
+			want: "my image.png",
+		}, {
+			name: "no path",
+			link: "https://example.com",
+			want: "download",
+		}, {
+			name: "root path",
+			link: "https://example.com/",
+			want: "download",
+		}, {
+			name: "parent directory",
+			link: "https://example.com/files/..",
+			want: "download",
+		}, {
+			name: "escaped parent directory",
+			link: "https://example.com/%2E%2E",
+			want: "download",
+		}, {
+			name: "escaped slash",
+			link: "https://example.com/a%2F..%2F..%2Fimage.png",
+			want: "image.png",
+		}, {
+			name: "escaped backslash",
+			link: "https://example.com/..%5C..%5Cimage.png",
+			want: "image.png",
+		}, {
+			name: "escaped backslash to parent directory",
+			link: "https://example.com/image.png%5C..",
+			want: "download",
+		}, {
+			name: "escaped trailing slash",
+			link: "https://example.com/files%2F",
+			want: "files",
+		}, {
+			name: "empty path",
+			link: "",
+			want: "download",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filenameFromLink(tt.link); got != tt.want {
+				t.Errorf("filenameFromLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findUnusedFilePath(t *testing.T) {
+	directory, tempDirError := ioutil.TempDir("", "cordless")
+	if tempDirError != nil {
+		t.Fatal(tempDirError)
+	}
+	defer os.RemoveAll(directory)
+
+	if got, _ := findUnusedFilePath(directory, "image.png"); got != filepath.Join(directory, "image.png") {
+		t.Errorf("findUnusedFilePath() = %v, want unchanged filename", got)
+	}
+
+	for _, filename := range []string{"..", "../image.png", "a/image.png"} {
+		if got, pathError := findUnusedFilePath(directory, filename); pathError == nil {
+			t.Errorf("findUnusedFilePath() = %v, want an error for '%s'", got, filename)
+		}
+	}
+
+	for _, filename := range []string{"image.png", "image (1).png"} {
+		writeError := ioutil.WriteFile(filepath.Join(directory, filename), nil, 0644)
+		if writeError != nil {
+			t.Fatal(writeError)
+		}
+	}
+
+	if got, _ := findUnusedFilePath(directory, "image.png"); got != filepath.Join(directory, "image (2).png") {
+		t.Errorf("findUnusedFilePath() = %v, want 'image (2).png'", got)
+	}
+}
+
+func TestLinkPicker_selectByNumber(t *testing.T) {
+	var links []*messageLink
+	for i := 1; i <= 12; i++ {
+		links = append(links, &messageLink{url: "https://example.com/" + strconv.Itoa(i)})
+	}
+	linkPicker := NewLinkPicker(tview.NewApplication(), links)
+
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "single digit", input: "3", want: 2},
+		{name: "two digits", input: "12", want: 11},
+		{name: "number too high starts over", input: "123", want: 2},
+		{name: "zero is ignored", input: "50", want: 4},
+		{name: "other keys start over", input: "1c2", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linkPicker.typedNumber = ""
+			for _, character := range tt.input {
+				linkPicker.handleListInput(tcell.NewEventKey(tcell.KeyRune, character, tcell.ModNone))
+			}
+			if got := linkPicker.list.GetCurrentItem(); got != tt.want {
+				t.Errorf("LinkPicker.list.GetCurrentItem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_downloadLink(t *testing.T) {
+	directory, tempDirError := ioutil.TempDir("", "cordless")
+	if tempDirError != nil {
+		t.Fatal(tempDirError)
+	}
+	defer os.RemoveAll(directory)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/image.png":
+			writer.Write([]byte("content"))
+		case "/slow.png":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	filePath, downloadError := downloadLink(server.URL+"/image.png", directory)
+	if downloadError != nil {
+		t.Fatalf("downloadLink() error = %v", downloadError)
+	}
+	if content, _ := ioutil.ReadFile(filePath); string(content) != "content" {
+		t.Errorf("downloaded content = '%s', want 'content'", content)
+	}
+
+	if _, downloadError := downloadLink(server.URL+"/missing.png", directory); downloadError == nil {
+		t.Error("downloadLink() wants an error for missing files")
+	}
+
+	oldTimeout := downloadClient.Timeout
+	defer func() { downloadClient.Timeout = oldTimeout }()
+	downloadClient.Timeout = 50 * time.Millisecond
+	if _, downloadError := downloadLink(server.URL+"/slow.png", directory); downloadError == nil {
+		t.Error("downloadLink() wants an error if the server doesn't respond in time")
+	}
+}
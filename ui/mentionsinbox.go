@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/mentions"
+	"github.com/Bios-Marcel/cordless/times"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
+)
+
+// newMention creates the inbox entry for a message that mentions the current
+// user. The guild may be nil for private channels.
+func newMention(message *discordgo.Message, channel *discordgo.Channel, guild *discordgo.Guild) *mentions.Mention {
+	mention := &mentions.Mention{
+		MessageID:  message.ID,
+		ChannelID:  channel.ID,
+		GuildID:    channel.GuildID,
+		AuthorName: message.Author.Username,
+		//The inbox shows a single line per mention.
+		Content:   strings.Join(strings.Fields(message.ContentWithMentionsReplaced()), " "),
+		Timestamp: string(message.Timestamp),
+	}
+
+	if guild != nil {
+		mention.GuildName = guild.Name
+		mention.ChannelName = "#" + channel.Name
+	} else {
+		mention.ChannelName = discordutil.GetPrivateChannelName(channel)
+	}
+
+	return mention
+}
+
+// formatMentionLocation returns where and when the mention happened, for
+// example "Guild - #channel - Author - 5m ago".
+func formatMentionLocation(mention *mentions.Mention, now time.Time) string {
+	location := mention.ChannelName + " - " + mention.AuthorName
+	if mention.GuildName != "" {
+		location = mention.GuildName + " - " + location
+	}
+
+	sentAt, parseError := discordgo.Timestamp(mention.Timestamp).Parse()
+	if parseError == nil {
+		location += " - " + times.TimeToRelativeString(sentAt, now)
+	}
+
+	return location
+}
+
+// MentionsInbox is an overlay that lists all messages that mentioned the
+// current user and haven't been marked as done yet.
+type MentionsInbox struct {
+	*tview.Flex
+
+	list        *tview.List
+	description *tview.TextView
+
+	mentions []*mentions.Mention
+
+	onJump     func(mention *mentions.Mention)
+	onMarkDone func(mention *mentions.Mention)
+	onClose    func()
+}
+
+// NewMentionsInbox creates a new inbox, showing the given mentions in the
+// given order.
+func NewMentionsInbox(inboxMentions []*mentions.Mention) *MentionsInbox {
+	mentionsInbox := &MentionsInbox{
+		Flex:        tview.NewFlex(),
+		list:        tview.NewList(),
+		description: tview.NewTextView(),
+		mentions:    inboxMentions,
+	}
+
+	mentionsInbox.list.SetBorder(true)
+	mentionsInbox.list.SetMainTextColor(config.GetTheme().PrimaryTextColor)
+	mentionsInbox.list.SetSecondaryTextColor(config.GetTheme().PrimaryTextColor)
+	mentionsInbox.list.SetSelectedTextColor(config.GetTheme().InverseTextColor)
+	mentionsInbox.list.SetSelectedBackgroundColor(config.GetTheme().PrimaryTextColor)
+	now := time.Now()
+	for _, mention := range inboxMentions {
+		mentionsInbox.list.AddItem(tview.Escape(formatMentionLocation(mention, now)),
+			"  "+tview.Escape(mention.Content), 0, nil)
+	}
+	mentionsInbox.list.SetInputCapture(mentionsInbox.handleListInput)
+	mentionsInbox.updateTitle()
+
+	primitiveBGColor := tviewutil.ColorToHex(config.GetTheme().PrimitiveBackgroundColor)
+	primaryTextColor := tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor)
+	mentionsInbox.description.SetDynamicColors(true)
+	mentionsInbox.description.SetText("[" + primaryTextColor + "][:" + primitiveBGColor + "]Enter [:" + primaryTextColor + "][" + primitiveBGColor + "]Jump to message" +
+		"[" + primaryTextColor + "][:" + primitiveBGColor + "]  d [:" + primaryTextColor + "][" + primitiveBGColor + "]Mark as done" +
+		"[" + primaryTextColor + "][:" + primitiveBGColor + "]  Esc [:" + primaryTextColor + "][" + primitiveBGColor + "]Close")
+
+	mentionsInbox.SetDirection(tview.FlexRow)
+	mentionsInbox.AddItem(mentionsInbox.list, 0, 1, false)
+	mentionsInbox.AddItem(mentionsInbox.description, 1, 0, false)
+
+	return mentionsInbox
+}
+
+// GetFocusTarget returns the component that should be focused when showing
+// the inbox.
+func (mentionsInbox *MentionsInbox) GetFocusTarget() tview.Primitive {
+	return mentionsInbox.list
+}
+
+// SetOnJump sets the handler that is called when the user wants to see the
+// message of a mention.
+func (mentionsInbox *MentionsInbox) SetOnJump(handler func(mention *mentions.Mention)) {
+	mentionsInbox.onJump = handler
+}
+
+// SetOnMarkDone sets the handler that is called when the user marks a
+// mention as done. The mention has already been removed from the list at
+// that point.
+func (mentionsInbox *MentionsInbox) SetOnMarkDone(handler func(mention *mentions.Mention)) {
+	mentionsInbox.onMarkDone = handler
+}
+
+// SetOnClose sets the handler that is called when the user closes the
+// inbox without jumping to a message.
+func (mentionsInbox *MentionsInbox) SetOnClose(handler func()) {
+	mentionsInbox.onClose = handler
+}
+
+func (mentionsInbox *MentionsInbox) updateTitle() {
+	if len(mentionsInbox.mentions) == 0 {
+		mentionsInbox.list.SetTitle("Mentions - nothing left to do")
+	} else {
+		mentionsInbox.list.SetTitle("Mentions (" + strconv.Itoa(len(mentionsInbox.mentions)) + ")")
+	}
+}
+
+// markCurrentAsDone removes the selected mention from the list and returns
+// it. If the list is empty, nil is returned.
+func (mentionsInbox *MentionsInbox) markCurrentAsDone() *mentions.Mention {
+	if len(mentionsInbox.mentions) == 0 {
+		return nil
+	}
+
+	index := mentionsInbox.list.GetCurrentItem()
+	mention := mentionsInbox.mentions[index]
+	mentionsInbox.mentions = append(mentionsInbox.mentions[:index], mentionsInbox.mentions[index+1:]...)
+	mentionsInbox.list.RemoveItem(index)
+	mentionsInbox.updateTitle()
+
+	return mention
+}
+
+func (mentionsInbox *MentionsInbox) handleListInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc {
+		if mentionsInbox.onClose != nil {
+			mentionsInbox.onClose()
+		}
+		return nil
+	}
+
+	if event.Key() == tcell.KeyEnter {
+		if len(mentionsInbox.mentions) > 0 && mentionsInbox.onJump != nil {
+			mentionsInbox.onJump(mentionsInbox.mentions[mentionsInbox.list.GetCurrentItem()])
+		}
+		return nil
+	}
+
+	if event.Key() == tcell.KeyRune && event.Rune() == 'd' {
+		mention := mentionsInbox.markCurrentAsDone()
+		if mention != nil && mentionsInbox.onMarkDone != nil {
+			mentionsInbox.onMarkDone(mention)
+		}
+		return nil
+	}
+
+	return event
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/cordless/mentions"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func Test_newMention(t *testing.T) {
+	message := &discordgo.Message{
+		ID:        "1",
+		Content:   "Hey,\n\nhave a look   at this",
+		Timestamp: "2019-10-12T10:00:00+00:00",
+		Author:    &discordgo.User{Username: "Author"},
+	}
+
+	guildChannel := &discordgo.Channel{ID: "2", GuildID: "3", Name: "general", Type: discordgo.ChannelTypeGuildText}
+	mention := newMention(message, guildChannel, &discordgo.Guild{ID: "3", Name: "Guild"})
+	if mention.Content != "Hey, have a look at this" {
+		t.Errorf("Content = %q, want the content on a single line", mention.Content)
+	}
+	if location := formatMentionLocation(mention, time.Date(2019, 10, 12, 10, 5, 0, 0, time.UTC)); location != "Guild - #general - Author - 5m ago" {
+		t.Errorf("formatMentionLocation() = %q, want %q", location, "Guild - #general - Author - 5m ago")
+	}
+
+	privateChannel := &discordgo.Channel{ID: "4", Type: discordgo.ChannelTypeDM, Recipients: []*discordgo.User{{Username: "Author"}}}
+	mention = newMention(message, privateChannel, nil)
+	if location := formatMentionLocation(mention, time.Date(2019, 10, 12, 12, 0, 0, 0, time.UTC)); location != "Author - Author - 2h ago" {
+		t.Errorf("formatMentionLocation() = %q, want %q", location, "Author - Author - 2h ago")
+	}
+}
+
+func TestMentionsInbox_markCurrentAsDone(t *testing.T) {
+	mentionsInbox := NewMentionsInbox([]*mentions.Mention{{MessageID: "1"}, {MessageID: "2"}})
+	mentionsInbox.list.SetCurrentItem(1)
+
+	if mention := mentionsInbox.markCurrentAsDone(); mention.MessageID != "2" {
+		t.Errorf("markCurrentAsDone() = %s, want 2", mention.MessageID)
+	}
+	if mention := mentionsInbox.markCurrentAsDone(); mention.MessageID != "1" {
+		t.Errorf("markCurrentAsDone() = %s, want 1", mention.MessageID)
+	}
+	if mention := mentionsInbox.markCurrentAsDone(); mention != nil {
+		t.Errorf("markCurrentAsDone() = %s, want nil", mention.MessageID)
+	}
+	if mentionsInbox.list.GetItemCount() != 0 {
+		t.Errorf("list contains %d items, want 0", mentionsInbox.list.GetItemCount())
+	}
+}
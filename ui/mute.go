@@ -0,0 +1,28 @@
+package ui
+
+import "time"
+
+// mutedIndicator is shown in front of muted guilds and channels.
+const mutedIndicator = "🔇"
+
+// withMutedIndicator prefixes the given text with the mutedIndicator if
+// muted is true.
+func withMutedIndicator(text string, muted bool) string {
+	if muted {
+		return mutedIndicator + text
+	}
+
+	return text
+}
+
+// muteDurationButtons are the choices offered when muting via shortcut, in
+// the order they are shown.
+var muteDurationButtons = []string{"1 hour", "8 hours", "Until I unmute", "Cancel"}
+
+// muteDurations maps the buttons of muteDurationButtons to the duration of
+// the mute. Zero means that the mute lasts until the user unmutes.
+var muteDurations = map[string]time.Duration{
+	"1 hour":         time.Hour,
+	"8 hours":        8 * time.Hour,
+	"Until I unmute": 0,
+}
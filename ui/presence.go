@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+// getPresenceStatus returns the status of the given user. For guild members,
+// the presences of the guild are used, otherwise the presences of friends.
+// Users without a known presence are considered offline.
+func getPresenceStatus(state *discordgo.State, guildID, userID string) discordgo.Status {
+	if guildID != "" {
+		presence, stateError := state.Presence(guildID, userID)
+		if stateError != nil || presence.Status == "" {
+			return discordgo.StatusOffline
+		}
+
+		return presence.Status
+	}
+
+	state.RLock()
+	defer state.RUnlock()
+	for _, presence := range state.Presences {
+		if presence.User.ID == userID && presence.Status != "" {
+			return presence.Status
+		}
+	}
+
+	return discordgo.StatusOffline
+}
+
+// isOffline decides whether a user with the given status is shown as being
+// offline. Invisible users appear offline to everyone else.
+func isOffline(status discordgo.Status) bool {
+	return status == discordgo.StatusOffline || status == discordgo.StatusInvisible
+}
+
+// presenceIndicator returns a dot representing the given status, followed by
+// a space. The colours are taken from the theme.
+func presenceIndicator(status discordgo.Status) string {
+	switch status {
+	case discordgo.StatusOnline:
+		return "[" + tviewutil.ColorToHex(config.GetTheme().OnlineColor) + "]●[-] "
+	case discordgo.StatusIdle:
+		return "[" + tviewutil.ColorToHex(config.GetTheme().IdleColor) + "]●[-] "
+	case discordgo.StatusDoNotDisturb:
+		return "[" + tviewutil.ColorToHex(config.GetTheme().DoNotDisturbColor) + "]●[-] "
+	default:
+		return "[" + tviewutil.ColorToHex(config.GetTheme().OfflineColor) + "]○[-] "
+	}
+}
+
+// updateFriendPresence applies the presence of a friend to the state. The
+// state only keeps the presences of guild members up to date by itself.
+func updateFriendPresence(state *discordgo.State, presence *discordgo.Presence) {
+	state.Lock()
+	defer state.Unlock()
+
+	for _, existingPresence := range state.Presences {
+		if existingPresence.User.ID == presence.User.ID {
+			existingPresence.Status = presence.Status
+			existingPresence.Game = presence.Game
+			return
+		}
+	}
+
+	state.Presences = append(state.Presences, presence)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func Test_getPresenceStatus(t *testing.T) {
+	state := discordgo.NewState()
+	stateError := state.GuildAdd(&discordgo.Guild{
+		ID: "G1",
+		Presences: []*discordgo.Presence{
+			{User: &discordgo.User{ID: "U1"}, Status: discordgo.StatusIdle},
+		},
+	})
+	if stateError != nil {
+		t.Fatalf("Error initializing state: %s", stateError)
+	}
+
+	if status := getPresenceStatus(state, "G1", "U1"); status != discordgo.StatusIdle {
+		t.Errorf("getPresenceStatus() = %s, want %s", status, discordgo.StatusIdle)
+	}
+	if status := getPresenceStatus(state, "G1", "U2"); status != discordgo.StatusOffline {
+		t.Errorf("getPresenceStatus() = %s, want %s", status, discordgo.StatusOffline)
+	}
+
+	updateFriendPresence(state, &discordgo.Presence{User: &discordgo.User{ID: "U2"}, Status: discordgo.StatusOnline})
+	if status := getPresenceStatus(state, "", "U2"); status != discordgo.StatusOnline {
+		t.Errorf("getPresenceStatus() = %s, want %s", status, discordgo.StatusOnline)
+	}
+
+	updateFriendPresence(state, &discordgo.Presence{User: &discordgo.User{ID: "U2"}, Status: discordgo.StatusDoNotDisturb})
+	if status := getPresenceStatus(state, "", "U2"); status != discordgo.StatusDoNotDisturb {
+		t.Errorf("getPresenceStatus() = %s, want %s", status, discordgo.StatusDoNotDisturb)
+	}
+	if len(state.Presences) != 1 {
+		t.Errorf("state contains %d friend presences, want 1", len(state.Presences))
+	}
+}
+
+func TestUserTree_offlineGroup(t *testing.T) {
+	oldGroupOfflineMembers := config.GetConfig().GroupOfflineMembers
+	config.GetConfig().GroupOfflineMembers = true
+	defer func() {
+		config.GetConfig().GroupOfflineMembers = oldGroupOfflineMembers
+	}()
+
+	state := discordgo.NewState()
+	stateError := state.GuildAdd(&discordgo.Guild{
+		ID: "G1",
+		Members: []*discordgo.Member{
+			{GuildID: "G1", User: &discordgo.User{ID: "U1", Username: "Online"}},
+			{GuildID: "G1", User: &discordgo.User{ID: "U2", Username: "Offline"}},
+		},
+		Presences: []*discordgo.Presence{
+			{User: &discordgo.User{ID: "U1"}, Status: discordgo.StatusOnline},
+		},
+	})
+	if stateError != nil {
+		t.Fatalf("Error initializing state: %s", stateError)
+	}
+
+	userTree := NewUserTree(state)
+	if loadError := userTree.LoadGuild("G1"); loadError != nil {
+		t.Fatalf("Error loading guild: %s", loadError)
+	}
+
+	rootChildren := userTree.rootNode.GetChildren()
+	if len(rootChildren) != 2 || rootChildren[0] != userTree.userNodes["U1"] || rootChildren[1] != userTree.offlineNode {
+		t.Fatalf("root should contain the online member followed by the offline group")
+	}
+	if offlineChildren := userTree.offlineNode.GetChildren(); len(offlineChildren) != 1 || offlineChildren[0] != userTree.userNodes["U2"] {
+		t.Errorf("offline group should contain the offline member")
+	}
+	if userTree.offlineNode.IsExpanded() {
+		t.Errorf("offline group should be collapsed")
+	}
+
+	state.PresenceAdd("G1", &discordgo.Presence{User: &discordgo.User{ID: "U2"}, Status: discordgo.StatusIdle})
+	userTree.UpdatePresence("G1", "U2")
+
+	rootChildren = userTree.rootNode.GetChildren()
+	if len(rootChildren) != 3 || rootChildren[1] != userTree.userNodes["U2"] || rootChildren[2] != userTree.offlineNode {
+		t.Errorf("member should have been moved in front of the offline group")
+	}
+	if len(userTree.offlineNode.GetChildren()) != 0 {
+		t.Errorf("offline group should be empty")
+	}
+	if text := userTree.userNodes["U2"].GetText(); text != presenceIndicator(discordgo.StatusIdle)+"Offline" {
+		t.Errorf("node text = %q, want the idle indicator", text)
+	}
+}
@@ -89,7 +89,7 @@ func (privateList *PrivateChatList) AddOrUpdateChannel(channel *discordgo.Channe
 	for _, node := range privateList.chatsNode.GetChildren() {
 		referenceChannelID, ok := node.GetReference().(string)
 		if ok && referenceChannelID == channel.ID {
-			node.SetText(discordutil.GetPrivateChannelName(channel))
+			node.SetText(privateList.getChannelText(channel))
 			return
 		}
 	}
@@ -109,12 +109,12 @@ func (privateList *PrivateChatList) AddOrUpdateChannel(channel *discordgo.Channe
 }
 
 func (privateList *PrivateChatList) prependChannel(channel *discordgo.Channel) {
-	newChildren := append([]*tview.TreeNode{createPrivateChannelNode(channel)}, privateList.chatsNode.GetChildren()...)
+	newChildren := append([]*tview.TreeNode{privateList.createPrivateChannelNode(channel)}, privateList.chatsNode.GetChildren()...)
 	privateList.chatsNode.SetChildren(newChildren)
 }
 
 func (privateList *PrivateChatList) addChannel(channel *discordgo.Channel) {
-	newNode := createPrivateChannelNode(channel)
+	newNode := privateList.createPrivateChannelNode(channel)
 	if !readstate.HasBeenRead(channel, channel.LastMessageID) {
 		privateList.privateChannelStates[newNode] = unread
 		newNode.SetColor(config.GetTheme().AttentionColor)
@@ -122,12 +122,30 @@ func (privateList *PrivateChatList) addChannel(channel *discordgo.Channel) {
 	privateList.chatsNode.AddChild(newNode)
 }
 
-func createPrivateChannelNode(channel *discordgo.Channel) *tview.TreeNode {
-	channelNode := tview.NewTreeNode(discordutil.GetPrivateChannelName(channel))
+func (privateList *PrivateChatList) createPrivateChannelNode(channel *discordgo.Channel) *tview.TreeNode {
+	channelNode := tview.NewTreeNode(privateList.getChannelText(channel))
 	channelNode.SetReference(channel.ID)
 	return channelNode
 }
 
+// getChannelText returns the name of the channel, followed by a badge for
+// its unread messages and mentions. Direct messages are prefixed with the
+// presence of the recipient and muted channels with the mutedIndicator.
+func (privateList *PrivateChatList) getChannelText(channel *discordgo.Channel) string {
+	name := withMutedIndicator(discordutil.GetPrivateChannelName(channel), readstate.IsChannelMuted(channel)) +
+		formatCountBadge(readstate.GetChannelCounts(channel))
+	if channel.Type == discordgo.ChannelTypeDM && len(channel.Recipients) > 0 {
+		return presenceIndicator(getPresenceStatus(privateList.state, "", channel.Recipients[0].ID)) + name
+	}
+
+	return name
+}
+
+// getFriendText returns the name of the user, prefixed with its presence.
+func (privateList *PrivateChatList) getFriendText(user *discordgo.User) string {
+	return presenceIndicator(getPresenceStatus(privateList.state, "", user.ID)) + discordutil.GetUserName(user)
+}
+
 // AddOrUpdateFriend either adds a friend or updates the node if it is
 // already present.
 func (privateList *PrivateChatList) AddOrUpdateFriend(user *discordgo.User) {
@@ -137,7 +155,7 @@ func (privateList *PrivateChatList) AddOrUpdateFriend(user *discordgo.User) {
 			channel, stateError := privateList.state.Channel(refrenceChannelID)
 			if stateError == nil && channel.Type == discordgo.ChannelTypeDM {
 				if channel.Recipients[0].ID == user.ID {
-					node.SetText(discordutil.GetUserName(user))
+					node.SetText(privateList.getFriendText(user))
 					return
 				}
 			}
@@ -147,7 +165,7 @@ func (privateList *PrivateChatList) AddOrUpdateFriend(user *discordgo.User) {
 	for _, node := range privateList.friendsNode.GetChildren() {
 		referenceUserID, ok := node.GetReference().(string)
 		if ok && referenceUserID == user.ID {
-			node.SetText(discordutil.GetUserName(user))
+			node.SetText(privateList.getFriendText(user))
 			return
 		}
 	}
@@ -156,11 +174,40 @@ func (privateList *PrivateChatList) AddOrUpdateFriend(user *discordgo.User) {
 }
 
 func (privateList *PrivateChatList) addFriend(user *discordgo.User) {
-	friendNode := tview.NewTreeNode(user.Username)
+	friendNode := tview.NewTreeNode(privateList.getFriendText(user))
 	friendNode.SetReference(user.ID)
 	privateList.friendsNode.AddChild(friendNode)
 }
 
+// UpdatePresence updates the presence shown for the given user, both in
+// the direct message with that user and in the list of friends.
+func (privateList *PrivateChatList) UpdatePresence(userID string) {
+	for _, node := range privateList.chatsNode.GetChildren() {
+		referenceChannelID, ok := node.GetReference().(string)
+		if ok {
+			channel, stateError := privateList.state.Channel(referenceChannelID)
+			if stateError == nil && channel.Type == discordgo.ChannelTypeDM &&
+				len(channel.Recipients) > 0 && channel.Recipients[0].ID == userID {
+				node.SetText(privateList.getChannelText(channel))
+				return
+			}
+		}
+	}
+
+	for _, relationship := range privateList.state.Relationships {
+		if relationship.User.ID == userID {
+			for _, node := range privateList.friendsNode.GetChildren() {
+				referenceUserID, ok := node.GetReference().(string)
+				if ok && referenceUserID == userID {
+					node.SetText(privateList.getFriendText(relationship.User))
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
 // RemoveFriend removes a friend node if present. This will not trigger any
 // action on the channel list.
 func (privateList *PrivateChatList) RemoveFriend(userID string) {
@@ -211,6 +258,7 @@ func (privateList *PrivateChatList) MarkChannelAsUnread(channel *discordgo.Chann
 		if ok && referenceChannelID == channel.ID {
 			privateList.privateChannelStates[node] = unread
 			node.SetColor(config.GetTheme().AttentionColor)
+			node.SetText(privateList.getChannelText(channel))
 			break
 		}
 	}
@@ -221,6 +269,9 @@ func (privateList *PrivateChatList) MarkChannelAsRead(channelID string) {
 	for _, node := range privateList.chatsNode.GetChildren() {
 		referenceChannelID, ok := node.GetReference().(string)
 		if ok && referenceChannelID == channelID {
+			if channel, stateError := privateList.state.Channel(channelID); stateError == nil {
+				node.SetText(privateList.getChannelText(channel))
+			}
 			if privateList.privateChannelStates[node] != loaded {
 				privateList.privateChannelStates[node] = read
 				node.SetColor(config.GetTheme().PrimaryTextColor)
@@ -230,6 +281,34 @@ func (privateList *PrivateChatList) MarkChannelAsRead(channelID string) {
 	}
 }
 
+// RefreshChannelStates updates the texts and colours of all private chats,
+// since muting or unmuting chats changes whether they are considered read.
+func (privateList *PrivateChatList) RefreshChannelStates() {
+	for _, node := range privateList.chatsNode.GetChildren() {
+		channelID, ok := node.GetReference().(string)
+		if !ok {
+			continue
+		}
+		channel, stateError := privateList.state.Channel(channelID)
+		if stateError != nil {
+			continue
+		}
+
+		node.SetText(privateList.getChannelText(channel))
+		if state, tracked := privateList.privateChannelStates[node]; tracked && state == loaded {
+			continue
+		}
+
+		if readstate.HasBeenRead(channel, channel.LastMessageID) {
+			privateList.privateChannelStates[node] = read
+			node.SetColor(config.GetTheme().PrimaryTextColor)
+		} else {
+			privateList.privateChannelStates[node] = unread
+			node.SetColor(config.GetTheme().AttentionColor)
+		}
+	}
+}
+
 // ReorderChannelList resorts the list of private chats according to their last
 // message times.
 func (privateList *PrivateChatList) ReorderChannelList() {
@@ -276,6 +355,7 @@ func (privateList *PrivateChatList) MarkChannelAsLoaded(channel *discordgo.Chann
 		if ok && referenceChannelID == channel.ID {
 			privateList.privateChannelStates[node] = loaded
 			node.SetColor(tview.Styles.ContrastBackgroundColor)
+			node.SetText(privateList.getChannelText(channel))
 			break
 		}
 	}
@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"sort"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/cordless/util/fuzzy"
+	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// maxQuickSwitchResults is the maximum amount of items that the quick
+	// switcher shows at once.
+	maxQuickSwitchResults = 50
+	// maxRecentQuickSwitches is the amount of picks that are remembered, so
+	// that they can be ranked higher.
+	maxRecentQuickSwitches = 10
+
+	// Unread and mentioned items get a bonus on top of their fuzzy score.
+	unreadQuickSwitchBonus    = 4
+	mentionedQuickSwitchBonus = 8
+	// recentQuickSwitchBonus is the bonus of the most recent pick. Older
+	// picks get less, down to a tenth of it for the oldest remembered pick.
+	// It is smaller than the score of a single matching letter, so that
+	// better matches still come first.
+	recentQuickSwitchBonus = 6
+)
+
+// quickSwitchItem is a guild or channel that can be picked in the quick
+// switcher.
+type quickSwitchItem struct {
+	guildID string
+	// channelID is empty for guilds.
+	channelID string
+	// text is what the item is displayed as and searched by.
+	text      string
+	unread    bool
+	mentioned bool
+}
+
+// key uniquely identifies the item, which is used for remembering recent
+// picks.
+func (item *quickSwitchItem) key() string {
+	if item.channelID != "" {
+		return item.channelID
+	}
+
+	return item.guildID
+}
+
+// rankQuickSwitchItems returns all items matching the query, best match
+// first. Recent picks, unread and mentioned items rank higher than other
+// items with a similar score. If the scores are equal, the more recent pick
+// comes first. If the query is empty, all items match.
+func rankQuickSwitchItems(query string, items []*quickSwitchItem, recent []string) []*quickSwitchItem {
+	type rankedItem struct {
+		item        *quickSwitchItem
+		score       float64
+		recentIndex int
+	}
+
+	recentIndices := make(map[string]int, len(recent))
+	for index, key := range recent {
+		recentIndices[key] = index
+	}
+
+	rankedItems := make([]*rankedItem, 0, len(items))
+	for _, item := range items {
+		score := fuzzy.Score(query, item.text)
+		if score < 0 {
+			continue
+		}
+
+		if item.mentioned {
+			score += mentionedQuickSwitchBonus
+		} else if item.unread {
+			score += unreadQuickSwitchBonus
+		}
+
+		recentIndex, isRecent := recentIndices[item.key()]
+		if isRecent {
+			score += recentQuickSwitchBonus * float64(maxRecentQuickSwitches-recentIndex) / maxRecentQuickSwitches
+		} else {
+			recentIndex = len(recent)
+		}
+
+		rankedItems = append(rankedItems, &rankedItem{item, score, recentIndex})
+	}
+
+	sort.SliceStable(rankedItems, func(a, b int) bool {
+		if rankedItems[a].score != rankedItems[b].score {
+			return rankedItems[a].score > rankedItems[b].score
+		}
+		return rankedItems[a].recentIndex < rankedItems[b].recentIndex
+	})
+
+	result := make([]*quickSwitchItem, 0, len(rankedItems))
+	for _, rankedItem := range rankedItems {
+		result = append(result, rankedItem.item)
+	}
+
+	return result
+}
+
+// addRecentQuickSwitch puts the given key in front of the recent picks,
+// dropping the oldest pick if there are too many.
+func addRecentQuickSwitch(recent []string, key string) []string {
+	newRecent := []string{key}
+	for _, recentKey := range recent {
+		if recentKey != key && len(newRecent) < maxRecentQuickSwitches {
+			newRecent = append(newRecent, recentKey)
+		}
+	}
+
+	return newRecent
+}
+
+// QuickSwitcher is an overlay that allows jumping to any guild or channel
+// by searching for its name.
+type QuickSwitcher struct {
+	*tview.Flex
+
+	input *tview.InputField
+	list  *tview.List
+
+	items   []*quickSwitchItem
+	recent  []string
+	results []*quickSwitchItem
+
+	onSelect func(item *quickSwitchItem)
+	onClose  func()
+}
+
+// NewQuickSwitcher creates a new quick switcher that searches through the
+// given items. Recent contains the keys of recently picked items.
+func NewQuickSwitcher(items []*quickSwitchItem, recent []string) *QuickSwitcher {
+	quickSwitcher := &QuickSwitcher{
+		Flex:   tview.NewFlex(),
+		input:  tview.NewInputField(),
+		list:   tview.NewList(),
+		items:  items,
+		recent: recent,
+	}
+
+	quickSwitcher.input.SetBorder(true)
+	quickSwitcher.input.SetTitle("Jump to")
+	quickSwitcher.input.SetFieldBackgroundColor(config.GetTheme().PrimitiveBackgroundColor)
+	quickSwitcher.input.SetFieldTextColor(config.GetTheme().PrimaryTextColor)
+	quickSwitcher.input.SetChangedFunc(quickSwitcher.search)
+	quickSwitcher.input.SetInputCapture(quickSwitcher.handleInput)
+
+	quickSwitcher.list.SetBorder(true)
+	quickSwitcher.list.ShowSecondaryText(false)
+	quickSwitcher.list.SetMainTextColor(config.GetTheme().PrimaryTextColor)
+	quickSwitcher.list.SetSelectedTextColor(config.GetTheme().InverseTextColor)
+	quickSwitcher.list.SetSelectedBackgroundColor(config.GetTheme().PrimaryTextColor)
+
+	quickSwitcher.SetDirection(tview.FlexRow)
+	quickSwitcher.AddItem(quickSwitcher.input, 3, 0, true)
+	quickSwitcher.AddItem(quickSwitcher.list, 0, 1, false)
+
+	quickSwitcher.search("")
+
+	return quickSwitcher
+}
+
+// GetFocusTarget returns the component that should be focused when showing
+// the quick switcher.
+func (quickSwitcher *QuickSwitcher) GetFocusTarget() tview.Primitive {
+	return quickSwitcher.input
+}
+
+// SetOnSelect sets the handler that is called when the user picks an item.
+func (quickSwitcher *QuickSwitcher) SetOnSelect(handler func(item *quickSwitchItem)) {
+	quickSwitcher.onSelect = handler
+}
+
+// SetOnClose sets the handler that is called when the user closes the quick
+// switcher without picking an item.
+func (quickSwitcher *QuickSwitcher) SetOnClose(handler func()) {
+	quickSwitcher.onClose = handler
+}
+
+// search updates the list to show the items matching the given query.
+func (quickSwitcher *QuickSwitcher) search(query string) {
+	quickSwitcher.results = rankQuickSwitchItems(query, quickSwitcher.items, quickSwitcher.recent)
+	if len(quickSwitcher.results) > maxQuickSwitchResults {
+		quickSwitcher.results = quickSwitcher.results[:maxQuickSwitchResults]
+	}
+
+	attentionColor := tviewutil.ColorToHex(config.GetTheme().AttentionColor)
+	quickSwitcher.list.Clear()
+	for _, item := range quickSwitcher.results {
+		text := tview.Escape(item.text)
+		if item.mentioned {
+			text = "[" + attentionColor + "]@ " + text
+		} else if item.unread {
+			text = "[" + attentionColor + "]" + text
+		}
+		quickSwitcher.list.AddItem(text, "", 0, nil)
+	}
+}
+
+func (quickSwitcher *QuickSwitcher) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		if quickSwitcher.onClose != nil {
+			quickSwitcher.onClose()
+		}
+		return nil
+	case tcell.KeyEnter:
+		if len(quickSwitcher.results) > 0 && quickSwitcher.onSelect != nil {
+			quickSwitcher.onSelect(quickSwitcher.results[quickSwitcher.list.GetCurrentItem()])
+		}
+		return nil
+	case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+		quickSwitcher.list.InputHandler()(event, nil)
+		return nil
+	}
+
+	return event
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func getItemKeys(items []*quickSwitchItem) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.key())
+	}
+	return keys
+}
+
+func Test_rankQuickSwitchItems(t *testing.T) {
+	items := []*quickSwitchItem{
+		{guildID: "G1", text: "Gophers"},
+		{guildID: "G1", channelID: "C1", text: "#general - Gophers"},
+		{guildID: "G1", channelID: "C2", text: "#generics - Gophers", unread: true},
+		{guildID: "G1", channelID: "C3", text: "#off-topic - Gophers", mentioned: true},
+		{channelID: "C4", text: "Marcel"},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		recent []string
+		want   []string
+	}{
+		{
+			name:  "empty query ranks mentioned and unread items first",
+			query: "",
+			want:  []string{"C3", "C2", "G1", "C1", "C4"},
+		}, {
+			name:  "non matching items are dropped",
+			query: "gen",
+			want:  []string{"C2", "C1"},
+		}, {
+			name:   "recent picks rank higher than similar matches",
+			query:  "gen",
+			recent: []string{"C4", "C1"},
+			want:   []string{"C1", "C2"},
+		}, {
+			name:   "better matches rank higher than recent picks",
+			query:  "gophers",
+			recent: []string{"C1"},
+			want:   []string{"G1", "C3", "C1", "C2"},
+		}, {
+			name:   "more recent picks rank higher",
+			query:  "",
+			recent: []string{"C4", "C1"},
+			want:   []string{"C3", "C4", "C1", "C2", "G1"},
+		}, {
+			name:  "no matches",
+			query: "xyz",
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getItemKeys(rankQuickSwitchItems(tt.query, items, tt.recent)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rankQuickSwitchItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_addRecentQuickSwitch(t *testing.T) {
+	recent := addRecentQuickSwitch(nil, "A")
+	recent = addRecentQuickSwitch(recent, "B")
+	recent = addRecentQuickSwitch(recent, "A")
+	if !reflect.DeepEqual(recent, []string{"A", "B"}) {
+		t.Errorf("addRecentQuickSwitch() = %v, want [A B]", recent)
+	}
+
+	for i := 0; i < maxRecentQuickSwitches; i++ {
+		recent = addRecentQuickSwitch(recent, string(rune('C'+i)))
+	}
+	if len(recent) != maxRecentQuickSwitches || recent[len(recent)-1] != "C" {
+		t.Errorf("addRecentQuickSwitch() = %v, want the oldest picks to be dropped", recent)
+	}
+}
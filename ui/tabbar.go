@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Bios-Marcel/cordless/config"
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/ui/tviewutil"
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/Bios-Marcel/tview"
+	"github.com/gdamore/tcell"
+)
+
+// channelTab is a channel that is open in the TabBar. Besides the channel,
+// it holds the state that is restored when switching back to the tab.
+type channelTab struct {
+	// channel is nil for a new tab that no channel has been loaded into yet.
+	channel *discordgo.Channel
+	// draft is the unsent text of the message input.
+	draft string
+	// chatViewState is nil if the tab hasn't been left since the channel
+	// has been loaded into it.
+	chatViewState *ChatViewState
+	unread        bool
+}
+
+// TabBar is a single line that shows all open channels, highlighting the
+// active one and the ones that have received new messages.
+type TabBar struct {
+	*tview.TextView
+
+	tabs        []*channelTab
+	activeIndex int
+	// tabEnds contains the column right after each rendered tab.
+	tabEnds []int
+
+	onTabSelect func(tab *channelTab)
+}
+
+// NewTabBar creates a TabBar without any tabs.
+func NewTabBar() *TabBar {
+	tabBar := &TabBar{
+		TextView:    tview.NewTextView(),
+		activeIndex: -1,
+	}
+
+	tabBar.SetDynamicColors(true)
+	tabBar.SetWrap(false)
+	tabBar.SetMouseHandler(func(event *tcell.EventMouse) bool {
+		if event.Buttons() != tcell.Button1 {
+			return false
+		}
+
+		x, _, _, _ := tabBar.GetRect()
+		column, _ := event.Position()
+		for index, tabEnd := range tabBar.tabEnds {
+			if column-x < tabEnd {
+				if tabBar.onTabSelect != nil {
+					tabBar.onTabSelect(tabBar.tabs[index])
+				}
+				break
+			}
+		}
+
+		return true
+	})
+	tabBar.render()
+
+	return tabBar
+}
+
+// SetOnTabSelect sets the handler that is called when the user clicks a tab.
+func (tabBar *TabBar) SetOnTabSelect(handler func(tab *channelTab)) {
+	tabBar.onTabSelect = handler
+}
+
+// GetActiveTab returns the currently active tab or nil if there is none.
+func (tabBar *TabBar) GetActiveTab() *channelTab {
+	if tabBar.activeIndex < 0 || tabBar.activeIndex >= len(tabBar.tabs) {
+		return nil
+	}
+
+	return tabBar.tabs[tabBar.activeIndex]
+}
+
+// GetTabCount returns the amount of open tabs.
+func (tabBar *TabBar) GetTabCount() int {
+	return len(tabBar.tabs)
+}
+
+// GetRelativeTab returns the tab that is offset tabs away from the active
+// one, wrapping around at both ends. If there are no tabs, nil is returned.
+func (tabBar *TabBar) GetRelativeTab(offset int) *channelTab {
+	if len(tabBar.tabs) == 0 {
+		return nil
+	}
+
+	index := (tabBar.activeIndex + offset) % len(tabBar.tabs)
+	if index < 0 {
+		index += len(tabBar.tabs)
+	}
+	return tabBar.tabs[index]
+}
+
+func (tabBar *TabBar) indexOfChannel(channelID string) int {
+	for index, tab := range tabBar.tabs {
+		if tab.channel != nil && tab.channel.ID == channelID {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// ShowChannel activates the tab that contains the given channel. If the
+// channel hasn't been opened in any tab, it replaces the channel of the
+// active tab. A new tab is only created if there's no active tab. A new tab
+// that is left without loading a channel into it, is closed.
+func (tabBar *TabBar) ShowChannel(channel *discordgo.Channel) *channelTab {
+	defer tabBar.render()
+
+	index := tabBar.indexOfChannel(channel.ID)
+	if index != -1 {
+		activeTab := tabBar.GetActiveTab()
+		if activeTab != nil && activeTab.channel == nil {
+			tabBar.removeTab(tabBar.activeIndex)
+			if index > tabBar.activeIndex {
+				index--
+			}
+		}
+
+		tabBar.activeIndex = index
+		tab := tabBar.tabs[index]
+		tab.unread = false
+		return tab
+	}
+
+	activeTab := tabBar.GetActiveTab()
+	if activeTab == nil {
+		activeTab = &channelTab{}
+		tabBar.tabs = append(tabBar.tabs, activeTab)
+		tabBar.activeIndex = len(tabBar.tabs) - 1
+	}
+
+	activeTab.channel = channel
+	activeTab.draft = ""
+	activeTab.chatViewState = nil
+	activeTab.unread = false
+	return activeTab
+}
+
+// OpenTab adds the channel as an inactive tab at the end. It's used for
+// restoring the tabs of a previous session.
+func (tabBar *TabBar) OpenTab(channel *discordgo.Channel, unread bool) {
+	tabBar.tabs = append(tabBar.tabs, &channelTab{
+		channel: channel,
+		unread:  unread,
+	})
+	tabBar.render()
+}
+
+// OpenEmptyTab adds an empty tab right after the active one and activates
+// it. The next channel that is shown will be loaded into that tab. If the
+// active tab is already empty, it's returned instead.
+func (tabBar *TabBar) OpenEmptyTab() *channelTab {
+	defer tabBar.render()
+
+	activeTab := tabBar.GetActiveTab()
+	if activeTab != nil && activeTab.channel == nil {
+		return activeTab
+	}
+
+	tab := &channelTab{}
+	tabBar.activeIndex++
+	tabBar.tabs = append(tabBar.tabs, nil)
+	copy(tabBar.tabs[tabBar.activeIndex+1:], tabBar.tabs[tabBar.activeIndex:])
+	tabBar.tabs[tabBar.activeIndex] = tab
+	return tab
+}
+
+// CloseActiveTab closes the active tab and returns the tab that should be
+// shown instead, preferring the one on the right. The returned tab isn't
+// activated, that happens once its channel is shown. If no tab is left, nil
+// is returned.
+func (tabBar *TabBar) CloseActiveTab() *channelTab {
+	defer tabBar.render()
+
+	if tabBar.GetActiveTab() == nil {
+		return nil
+	}
+
+	closedIndex := tabBar.activeIndex
+	tabBar.removeTab(closedIndex)
+	tabBar.activeIndex = -1
+	if len(tabBar.tabs) == 0 {
+		return nil
+	}
+
+	if closedIndex >= len(tabBar.tabs) {
+		return tabBar.tabs[len(tabBar.tabs)-1]
+	}
+	return tabBar.tabs[closedIndex]
+}
+
+// CloseChannel closes the tab that contains the given channel, unless it's
+// the active one.
+func (tabBar *TabBar) CloseChannel(channelID string) {
+	index := tabBar.indexOfChannel(channelID)
+	if index == -1 || index == tabBar.activeIndex {
+		return
+	}
+
+	tabBar.removeTab(index)
+	if tabBar.activeIndex > index {
+		tabBar.activeIndex--
+	}
+	tabBar.render()
+}
+
+func (tabBar *TabBar) removeTab(index int) {
+	tabBar.tabs = append(tabBar.tabs[:index], tabBar.tabs[index+1:]...)
+}
+
+// MoveActiveTab moves the active tab by the given amount of positions.
+// Tabs can't be moved past the start or the end.
+func (tabBar *TabBar) MoveActiveTab(offset int) {
+	if tabBar.GetActiveTab() == nil {
+		return
+	}
+
+	newIndex := tabBar.activeIndex + offset
+	if newIndex < 0 {
+		newIndex = 0
+	} else if newIndex >= len(tabBar.tabs) {
+		newIndex = len(tabBar.tabs) - 1
+	}
+
+	activeTab := tabBar.tabs[tabBar.activeIndex]
+	if newIndex < tabBar.activeIndex {
+		copy(tabBar.tabs[newIndex+1:tabBar.activeIndex+1], tabBar.tabs[newIndex:tabBar.activeIndex])
+	} else {
+		copy(tabBar.tabs[tabBar.activeIndex:newIndex], tabBar.tabs[tabBar.activeIndex+1:newIndex+1])
+	}
+	tabBar.tabs[newIndex] = activeTab
+	tabBar.activeIndex = newIndex
+	tabBar.render()
+}
+
+// MarkChannelAsUnread highlights the tab of the given channel, unless it's
+// the active one.
+func (tabBar *TabBar) MarkChannelAsUnread(channelID string) {
+	index := tabBar.indexOfChannel(channelID)
+	if index != -1 && index != tabBar.activeIndex && !tabBar.tabs[index].unread {
+		tabBar.tabs[index].unread = true
+		tabBar.render()
+	}
+}
+
+// GetChannelIDs returns the IDs of all channels that are open, in the order
+// of the tabs, and the index of the active one. Empty tabs are ignored.
+func (tabBar *TabBar) GetChannelIDs() ([]string, int) {
+	channelIDs := make([]string, 0, len(tabBar.tabs))
+	activeIndex := 0
+	for index, tab := range tabBar.tabs {
+		if tab.channel == nil {
+			continue
+		}
+
+		if index == tabBar.activeIndex {
+			activeIndex = len(channelIDs)
+		}
+		channelIDs = append(channelIDs, tab.channel.ID)
+	}
+
+	return channelIDs, activeIndex
+}
+
+func getTabName(channel *discordgo.Channel) string {
+	if channel == nil {
+		return "New tab"
+	}
+
+	if channel.Type == discordgo.ChannelTypeGuildText {
+		return "#" + channel.Name
+	}
+
+	return discordutil.GetPrivateChannelName(channel)
+}
+
+func (tabBar *TabBar) render() {
+	var builder strings.Builder
+	tabBar.tabEnds = tabBar.tabEnds[:0]
+	column := 0
+	for index, tab := range tabBar.tabs {
+		name := " " + getTabName(tab.channel) + " "
+		if index == tabBar.activeIndex {
+			builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().InverseTextColor) + ":" +
+				tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + "]")
+		} else if tab.unread {
+			builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().AttentionColor) + ":" +
+				tviewutil.ColorToHex(config.GetTheme().PrimitiveBackgroundColor) + "]")
+		} else {
+			builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + ":" +
+				tviewutil.ColorToHex(config.GetTheme().PrimitiveBackgroundColor) + "]")
+		}
+		builder.WriteString(tview.Escape(name))
+		builder.WriteString("[" + tviewutil.ColorToHex(config.GetTheme().PrimaryTextColor) + ":" +
+			tviewutil.ColorToHex(config.GetTheme().PrimitiveBackgroundColor) + "]|")
+
+		column += tview.TaggedStringWidth(tview.Escape(name)) + 1
+		tabBar.tabEnds = append(tabBar.tabEnds, column)
+	}
+
+	tabBar.SetText(builder.String())
+	//A single tab is the same as having no tabs at all.
+	tabBar.SetVisible(len(tabBar.tabs) > 1)
+}
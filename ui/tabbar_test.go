@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Bios-Marcel/discordgo"
+	"github.com/gdamore/tcell"
+)
+
+func createTestChannel(id string) *discordgo.Channel {
+	return &discordgo.Channel{
+		ID:   id,
+		Name: id,
+		Type: discordgo.ChannelTypeGuildText,
+	}
+}
+
+func assertTabs(t *testing.T, tabBar *TabBar, wantIDs []string, wantActive int) {
+	t.Helper()
+	channelIDs, activeIndex := tabBar.GetChannelIDs()
+	if !reflect.DeepEqual(channelIDs, wantIDs) || activeIndex != wantActive {
+		t.Errorf("tabs = %v with %d being active, want %v with %d being active", channelIDs, activeIndex, wantIDs, wantActive)
+	}
+}
+
+func TestTabBar_ShowChannel(t *testing.T) {
+	tabBar := NewTabBar()
+	if tabBar.GetActiveTab() != nil {
+		t.Error("new TabBar has an active tab")
+	}
+
+	first := tabBar.ShowChannel(createTestChannel("1"))
+	assertTabs(t, tabBar, []string{"1"}, 0)
+	if tabBar.IsVisible() {
+		t.Error("TabBar with a single tab should be invisible")
+	}
+
+	//Without an empty tab, the channel of the active tab is replaced.
+	first.draft = "draft"
+	if tab := tabBar.ShowChannel(createTestChannel("2")); tab != first || tab.draft != "" {
+		t.Error("channel of active tab hasn't been replaced")
+	}
+	assertTabs(t, tabBar, []string{"2"}, 0)
+
+	tabBar.OpenEmptyTab()
+	tabBar.ShowChannel(createTestChannel("3"))
+	assertTabs(t, tabBar, []string{"2", "3"}, 1)
+	if !tabBar.IsVisible() {
+		t.Error("TabBar with multiple tabs should be visible")
+	}
+
+	//Empty tabs are closed when showing a channel that is already open.
+	tabBar.OpenEmptyTab()
+	if tab := tabBar.ShowChannel(createTestChannel("2")); tab != first {
+		t.Error("tab of already open channel hasn't been activated")
+	}
+	assertTabs(t, tabBar, []string{"2", "3"}, 0)
+	if tabBar.GetTabCount() != 2 {
+		t.Errorf("%d tabs open, want 2", tabBar.GetTabCount())
+	}
+}
+
+func TestTabBar_CloseActiveTab(t *testing.T) {
+	tabBar := NewTabBar()
+	for _, channelID := range []string{"1", "2", "3"} {
+		tabBar.OpenTab(createTestChannel(channelID), false)
+	}
+	tabBar.ShowChannel(createTestChannel("2"))
+
+	if next := tabBar.CloseActiveTab(); next.channel.ID != "3" {
+		t.Errorf("CloseActiveTab() = %s, want tab on the right", next.channel.ID)
+	}
+	tabBar.ShowChannel(createTestChannel("3"))
+	if next := tabBar.CloseActiveTab(); next.channel.ID != "1" {
+		t.Errorf("CloseActiveTab() = %s, want tab on the left", next.channel.ID)
+	}
+	tabBar.ShowChannel(createTestChannel("1"))
+	if next := tabBar.CloseActiveTab(); next != nil {
+		t.Errorf("CloseActiveTab() = %s, want nil", next.channel.ID)
+	}
+}
+
+func TestTabBar_MoveActiveTab(t *testing.T) {
+	tabBar := NewTabBar()
+	for _, channelID := range []string{"1", "2", "3"} {
+		tabBar.OpenTab(createTestChannel(channelID), false)
+	}
+	tabBar.ShowChannel(createTestChannel("1"))
+
+	tabBar.MoveActiveTab(1)
+	assertTabs(t, tabBar, []string{"2", "1", "3"}, 1)
+	tabBar.MoveActiveTab(5)
+	assertTabs(t, tabBar, []string{"2", "3", "1"}, 2)
+	tabBar.MoveActiveTab(-2)
+	assertTabs(t, tabBar, []string{"1", "2", "3"}, 0)
+	tabBar.MoveActiveTab(-1)
+	assertTabs(t, tabBar, []string{"1", "2", "3"}, 0)
+
+	if tab := tabBar.GetRelativeTab(-1); tab.channel.ID != "3" {
+		t.Errorf("GetRelativeTab(-1) = %s, want last tab", tab.channel.ID)
+	}
+}
+
+func TestTabBar_MarkChannelAsUnread(t *testing.T) {
+	tabBar := NewTabBar()
+	tabBar.OpenTab(createTestChannel("1"), false)
+	tabBar.OpenTab(createTestChannel("2"), false)
+	tabBar.ShowChannel(createTestChannel("1"))
+
+	tabBar.MarkChannelAsUnread("1")
+	tabBar.MarkChannelAsUnread("2")
+	if tabBar.tabs[0].unread {
+		t.Error("active tab has been marked as unread")
+	}
+	if !tabBar.tabs[1].unread {
+		t.Error("inactive tab hasn't been marked as unread")
+	}
+
+	if tab := tabBar.ShowChannel(createTestChannel("2")); tab.unread {
+		t.Error("activated tab is still marked as unread")
+	}
+}
+
+func TestChatView_RestoreState(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if initError := screen.Init(); initError != nil {
+		t.Fatal(initError)
+	}
+	defer screen.Fini()
+
+	chatView := createTestChatView(200)
+	chatView.selection = 150
+	chatView.updateHighlights()
+	chatView.internalTextView.Draw(screen)
+	chatView.internalTextView.ScrollTo(100, 0)
+	chatView.internalTextView.Draw(screen)
+	state := chatView.GetState()
+	if state.firstVisibleMessageID == "" {
+		t.Fatal("view is considered to be scrolled to the end")
+	}
+
+	//Only the latest messages are loaded again, the older ones come from the
+	//state.
+	chatView.SetMessages(state.messages[100:])
+	chatView.ClearSelection()
+	chatView.internalTextView.ScrollToEnd()
+	chatView.RestoreState(state)
+	//Drawing applies pending scroll requests, which mustn't override the
+	//restored scroll position.
+	chatView.internalTextView.Draw(screen)
+
+	if len(chatView.data) != 200 {
+		t.Errorf("%d messages after restoring, want 200", len(chatView.data))
+	}
+	if chatView.selection != 150 {
+		t.Errorf("selection = %d, want 150", chatView.selection)
+	}
+	restoredState := chatView.GetState()
+	if restoredState.selectedMessageID != state.selectedMessageID {
+		t.Errorf("selectedMessageID = %s, want %s", restoredState.selectedMessageID, state.selectedMessageID)
+	}
+	if restoredState.firstVisibleMessageID != state.firstVisibleMessageID {
+		t.Errorf("firstVisibleMessageID = %s, want %s", restoredState.firstVisibleMessageID, state.firstVisibleMessageID)
+	}
+}
@@ -2,12 +2,20 @@ package tviewutil
 
 import (
 	"fmt"
+	"math"
+	"sync"
 
 	"github.com/gdamore/tcell"
 )
 
 var (
 	colorCache = make(map[tcell.Color]string)
+
+	// terminalColors is the amount of colours the terminal can display.
+	// Zero means that the amount is unknown and colours aren't mapped.
+	terminalColors     int
+	terminalColorCache = make(map[tcell.Color]tcell.Color)
+	terminalColorMutex = &sync.Mutex{}
 )
 
 // ColorToHex converts the tcell.Color to it's hexadecimal presentation
@@ -23,3 +31,82 @@ func ColorToHex(color tcell.Color) string {
 
 	return newValue
 }
+
+// SetTerminalColors sets the amount of colours that the terminal can
+// display, as reported by tcell.Screen.Colors.
+func SetTerminalColors(count int) {
+	terminalColorMutex.Lock()
+	defer terminalColorMutex.Unlock()
+
+	if terminalColors != count {
+		terminalColors = count
+		terminalColorCache = make(map[tcell.Color]tcell.Color)
+	}
+}
+
+// NearestTerminalColor returns the colour that the terminal can display
+// and that is closest to the given colour. If the terminal supports
+// true colour or the amount of colours is unknown, the colour is returned
+// unchanged.
+func NearestTerminalColor(color tcell.Color) tcell.Color {
+	terminalColorMutex.Lock()
+	defer terminalColorMutex.Unlock()
+
+	if terminalColors <= 0 || terminalColors > 256 {
+		return color
+	}
+
+	nearestColor, found := terminalColorCache[color]
+	if found {
+		return nearestColor
+	}
+
+	palette := make([]tcell.Color, terminalColors)
+	for index := range palette {
+		palette[index] = tcell.Color(index)
+	}
+	nearestColor = tcell.FindColor(color, palette)
+	terminalColorCache[color] = nearestColor
+
+	return nearestColor
+}
+
+// ContrastRatio calculates the contrast ratio between two colours as defined
+// by the WCAG. The result ranges from 1, meaning no contrast at all, to 21,
+// which is the contrast between black and white. If any of the colours has
+// no RGB value, such as tcell.ColorDefault, 0 is returned.
+func ContrastRatio(a, b tcell.Color) float64 {
+	luminanceA, validA := relativeLuminance(a)
+	luminanceB, validB := relativeLuminance(b)
+	if !validA || !validB {
+		return 0
+	}
+
+	if luminanceA < luminanceB {
+		luminanceA, luminanceB = luminanceB, luminanceA
+	}
+
+	return (luminanceA + 0.05) / (luminanceB + 0.05)
+}
+
+func relativeLuminance(color tcell.Color) (float64, bool) {
+	//ColorDefault would be treated as white by tcell.
+	if color == tcell.ColorDefault {
+		return 0, false
+	}
+
+	r, g, b := color.RGB()
+	if r < 0 || g < 0 || b < 0 {
+		return 0, false
+	}
+
+	linearize := func(value int32) float64 {
+		channel := float64(value) / 255
+		if channel <= 0.03928 {
+			return channel / 12.92
+		}
+		return math.Pow((channel+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linearize(r) + 0.7152*linearize(g) + 0.0722*linearize(b), true
+}
@@ -1,6 +1,7 @@
 package tviewutil
 
 import (
+	"math"
 	"testing"
 
 	"github.com/gdamore/tcell"
@@ -43,3 +44,47 @@ func TestColorToHex(t *testing.T) {
 		})
 	}
 }
+
+func TestNearestTerminalColor(t *testing.T) {
+	defer SetTerminalColors(0)
+
+	almostRed := tcell.NewRGBColor(250, 10, 5)
+	if got := NearestTerminalColor(almostRed); got != almostRed {
+		t.Errorf("NearestTerminalColor() = %v, want the colour to be unchanged", got)
+	}
+
+	SetTerminalColors(1 << 24)
+	if got := NearestTerminalColor(almostRed); got != almostRed {
+		t.Errorf("NearestTerminalColor() = %v, want the colour to be unchanged", got)
+	}
+
+	SetTerminalColors(8)
+	if got := NearestTerminalColor(almostRed); got != tcell.ColorMaroon {
+		t.Errorf("NearestTerminalColor() = %v, want %v", got, tcell.ColorMaroon)
+	}
+
+	SetTerminalColors(16)
+	if got := NearestTerminalColor(almostRed); got != tcell.ColorRed {
+		t.Errorf("NearestTerminalColor() = %v, want %v", got, tcell.ColorRed)
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b tcell.Color
+		want float64
+	}{
+		{name: "black on white", a: tcell.NewRGBColor(0, 0, 0), b: tcell.NewRGBColor(255, 255, 255), want: 21},
+		{name: "white on black", a: tcell.NewRGBColor(255, 255, 255), b: tcell.ColorBlack, want: 21},
+		{name: "same colour", a: tcell.ColorRed, b: tcell.ColorRed, want: 1},
+		{name: "default colour", a: tcell.ColorDefault, b: tcell.ColorBlack, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("ContrastRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -27,3 +27,23 @@ func CreateFocusTextViewOnTypeInputHandler(app *tview.Application, component *tv
 
 	return eventHandler
 }
+
+// FindNodeByReference returns the first node in the tree below the given root
+// that references the given value. If there's none, nil is returned.
+func FindNodeByReference(root *tview.TreeNode, reference interface{}) *tview.TreeNode {
+	var foundNode *tview.TreeNode
+	root.Walk(func(node, parent *tview.TreeNode) bool {
+		if foundNode != nil {
+			return false
+		}
+
+		if node.GetReference() == reference {
+			foundNode = node
+			return false
+		}
+
+		return true
+	})
+
+	return foundNode
+}
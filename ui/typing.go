@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bios-Marcel/tview"
+)
+
+const (
+	// typingDuration is how long a user is shown as typing after the last
+	// typing notification. Discord clients send a new notification about
+	// every ten seconds while the user keeps typing.
+	typingDuration = 10 * time.Second
+	// typingNotificationInterval is the minimum time between two typing
+	// notifications that we send ourselves.
+	typingNotificationInterval = 8 * time.Second
+)
+
+// typingUser is a user that has recently started typing.
+type typingUser struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// typingUsers keeps track of the users that are currently typing in each
+// channel.
+type typingUsers struct {
+	mutex    *sync.Mutex
+	channels map[string][]*typingUser
+}
+
+func newTypingUsers() *typingUsers {
+	return &typingUsers{
+		mutex:    &sync.Mutex{},
+		channels: make(map[string][]*typingUser),
+	}
+}
+
+// add marks the user as typing in the given channel until typingDuration
+// has passed. Users that are already typing keep their position.
+func (typing *typingUsers) add(channelID, userID string, now time.Time) {
+	typing.mutex.Lock()
+	defer typing.mutex.Unlock()
+
+	for _, user := range typing.channels[channelID] {
+		if user.userID == userID {
+			user.expiresAt = now.Add(typingDuration)
+			return
+		}
+	}
+
+	typing.channels[channelID] = append(typing.channels[channelID], &typingUser{
+		userID:    userID,
+		expiresAt: now.Add(typingDuration),
+	})
+}
+
+// remove stops showing the user as typing in the given channel. This should
+// be called once the user has sent a message. The return value indicates
+// whether the user has been typing.
+func (typing *typingUsers) remove(channelID, userID string) bool {
+	typing.mutex.Lock()
+	defer typing.mutex.Unlock()
+
+	users := typing.channels[channelID]
+	for index, user := range users {
+		if user.userID == userID {
+			typing.channels[channelID] = append(users[:index], users[index+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// get returns the IDs of all users that are typing in the given channel, in
+// the order they started typing. Expired entries are dropped.
+func (typing *typingUsers) get(channelID string, now time.Time) []string {
+	typing.mutex.Lock()
+	defer typing.mutex.Unlock()
+
+	var userIDs []string
+	stillTyping := typing.channels[channelID][:0]
+	for _, user := range typing.channels[channelID] {
+		if now.Before(user.expiresAt) {
+			stillTyping = append(stillTyping, user)
+			userIDs = append(userIDs, user.userID)
+		}
+	}
+
+	if len(stillTyping) == 0 {
+		delete(typing.channels, channelID)
+	} else {
+		typing.channels[channelID] = stillTyping
+	}
+
+	return userIDs
+}
+
+// formatTypingText creates the text that tells who is typing, for example
+// "A and B are typing…". The names are escaped, since they may contain
+// anything that looks like a colour tag. If nobody is typing, an empty string
+// is returned.
+func formatTypingText(names []string) string {
+	escapedNames := make([]string, 0, len(names))
+	for _, name := range names {
+		escapedNames = append(escapedNames, tview.Escape(name))
+	}
+	names = escapedNames
+
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0] + " is typing…"
+	case 2, 3:
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " are typing…"
+	default:
+		return "Several people are typing…"
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypingUsers(t *testing.T) {
+	typing := newTypingUsers()
+	start := time.Date(2019, 10, 12, 10, 0, 0, 0, time.UTC)
+
+	typing.add("channel", "1", start)
+	typing.add("channel", "2", start.Add(5*time.Second))
+	typing.add("other", "3", start)
+	if userIDs := typing.get("channel", start.Add(6*time.Second)); !reflect.DeepEqual(userIDs, []string{"1", "2"}) {
+		t.Errorf("get() = %v, want [1 2]", userIDs)
+	}
+
+	//Typing again extends the duration without changing the order.
+	typing.add("channel", "1", start.Add(8*time.Second))
+	if userIDs := typing.get("channel", start.Add(16*time.Second)); !reflect.DeepEqual(userIDs, []string{"1"}) {
+		t.Errorf("get() = %v, want [1]", userIDs)
+	}
+	if userIDs := typing.get("channel", start.Add(18*time.Second)); userIDs != nil {
+		t.Errorf("get() = %v, want no users", userIDs)
+	}
+
+	typing.add("other", "4", start)
+	if !typing.remove("other", "3") || typing.remove("other", "3") {
+		t.Error("remove() should only succeed for typing users")
+	}
+	if userIDs := typing.get("other", start); !reflect.DeepEqual(userIDs, []string{"4"}) {
+		t.Errorf("get() = %v, want [4]", userIDs)
+	}
+}
+
+func Test_formatTypingText(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{names: nil, want: ""},
+		{names: []string{"A"}, want: "A is typing…"},
+		{names: []string{"A", "B"}, want: "A and B are typing…"},
+		{names: []string{"A", "B", "C"}, want: "A, B and C are typing…"},
+		{names: []string{"A", "B", "C", "D"}, want: "Several people are typing…"},
+		{names: []string{"[red]A", "B[::b]"}, want: "[red[]A and B[::b[] are typing…"},
+	}
+	for _, tt := range tests {
+		if got := formatTypingText(tt.names); got != tt.want {
+			t.Errorf("formatTypingText(%v) = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
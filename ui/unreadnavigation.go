@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"sort"
+
+	"github.com/Bios-Marcel/cordless/discordutil"
+	"github.com/Bios-Marcel/cordless/readstate"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+// getChannelsInTreeOrder returns the readable text channels of the given
+// guild in the same order as the ChannelTree shows them. That is all
+// channels without a category first, followed by the channels of each
+// category.
+func getChannelsInTreeOrder(state *discordgo.State, guild *discordgo.Guild) []*discordgo.Channel {
+	channels := make([]*discordgo.Channel, len(guild.Channels))
+	copy(channels, guild.Channels)
+	sort.SliceStable(channels, func(a, b int) bool {
+		return channels[a].Position < channels[b].Position
+	})
+
+	var result []*discordgo.Channel
+	for _, channel := range channels {
+		if channel.Type == discordgo.ChannelTypeGuildText && channel.ParentID == "" &&
+			discordutil.HasReadMessagesPermission(channel.ID, state) {
+			result = append(result, channel)
+		}
+	}
+
+	for _, category := range channels {
+		if category.Type != discordgo.ChannelTypeGuildCategory ||
+			!discordutil.HasReadMessagesPermission(category.ID, state) {
+			continue
+		}
+
+		for _, channel := range channels {
+			if channel.Type == discordgo.ChannelTypeGuildText && channel.ParentID == category.ID &&
+				discordutil.HasReadMessagesPermission(channel.ID, state) {
+				result = append(result, channel)
+			}
+		}
+	}
+
+	return result
+}
+
+// findNextUnreadChannel returns the first of the given channels that has
+// unread messages or, if mentionsOnly is set, unread mentions. Muted
+// channels and the currently loaded channel are skipped. If there's no such
+// channel, nil is returned.
+func findNextUnreadChannel(channels []*discordgo.Channel, currentChannelID string, mentionsOnly bool) *discordgo.Channel {
+	for _, channel := range channels {
+		if channel.ID == currentChannelID || readstate.IsChannelMuted(channel) {
+			continue
+		}
+
+		if readstate.GetMentionCount(channel.ID) > 0 {
+			return channel
+		}
+
+		if !mentionsOnly && !readstate.HasBeenRead(channel, channel.LastMessageID) {
+			return channel
+		}
+	}
+
+	return nil
+}
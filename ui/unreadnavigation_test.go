@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/cordless/readstate"
+	"github.com/Bios-Marcel/discordgo"
+)
+
+func getChannelIDs(channels []*discordgo.Channel) []string {
+	ids := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		ids = append(ids, channel.ID)
+	}
+	return ids
+}
+
+func TestUnreadNavigation(t *testing.T) {
+	state := discordgo.NewState()
+	guild := &discordgo.Guild{
+		ID:      "G1",
+		OwnerID: "U1",
+		Members: []*discordgo.Member{{GuildID: "G1", User: &discordgo.User{ID: "U1"}}},
+		Channels: []*discordgo.Channel{
+			{ID: "C1", GuildID: "G1", Type: discordgo.ChannelTypeGuildText, ParentID: "K1", Position: 1, LastMessageID: "1"},
+			{ID: "K1", GuildID: "G1", Type: discordgo.ChannelTypeGuildCategory, Position: 1},
+			{ID: "C2", GuildID: "G1", Type: discordgo.ChannelTypeGuildText, Position: 2, LastMessageID: "1"},
+			{ID: "C3", GuildID: "G1", Type: discordgo.ChannelTypeGuildText, ParentID: "K1", Position: 0, LastMessageID: "1"},
+		},
+	}
+	if stateError := state.GuildAdd(guild); stateError != nil {
+		t.Fatalf("Error initializing state: %s", stateError)
+	}
+	state.Ready = discordgo.Ready{
+		User: &discordgo.User{ID: "U1"},
+		ReadState: []*discordgo.ReadState{
+			{ID: "C1", LastMessageID: "0", MentionCount: 1},
+			{ID: "C2", LastMessageID: "1"},
+			{ID: "C3", LastMessageID: "0"},
+		},
+		UserGuildSettings: []*discordgo.UserGuildSettings{
+			{
+				GuildID: "G1",
+				ChannelOverrides: []*discordgo.UserGuildSettingsChannelOverride{
+					{ChannelID: "C3", Muted: true},
+				},
+			},
+		},
+	}
+	readstate.Load(state)
+
+	channels := getChannelsInTreeOrder(state, guild)
+	if ids := getChannelIDs(channels); len(ids) != 3 || ids[0] != "C2" || ids[1] != "C3" || ids[2] != "C1" {
+		t.Fatalf("getChannelsInTreeOrder() = %v, want [C2 C3 C1]", ids)
+	}
+
+	//C2 has been read and C3 is muted.
+	if next := findNextUnreadChannel(channels, "", false); next == nil || next.ID != "C1" {
+		t.Errorf("findNextUnreadChannel() = %v, want C1", next)
+	}
+	if next := findNextUnreadChannel(channels, "C1", false); next != nil {
+		t.Errorf("findNextUnreadChannel() = %v, want nil", next)
+	}
+
+	readstate.UpdateReadLocal("C1", "1")
+	if next := findNextUnreadChannel(channels, "", true); next != nil {
+		t.Errorf("findNextUnreadChannel() = %v, want nil", next)
+	}
+}
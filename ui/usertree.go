@@ -18,9 +18,20 @@ type UserTree struct {
 	state *discordgo.State
 
 	userNodes map[string]*tview.TreeNode
+	// userParents contains the node that each user node has been added to.
+	userParents map[string]*tview.TreeNode
 
 	roleNodes map[string]*tview.TreeNode
 	roles     []*discordgo.Role
+	// offlineNode contains all offline members if GroupOfflineMembers is
+	// enabled. It's always the last node.
+	offlineNode *tview.TreeNode
+
+	// guildID is the guild whose members are shown. It's empty for groups.
+	guildID string
+	// groupChannelID is the channel whose recipients are shown. It's empty
+	// for guilds.
+	groupChannelID string
 }
 
 // NewUserTree creates a new pre-configured UserTree that is empty.
@@ -28,6 +39,7 @@ func NewUserTree(state *discordgo.State) *UserTree {
 	userTree := &UserTree{
 		state:            state,
 		userNodes:        make(map[string]*tview.TreeNode),
+		userParents:      make(map[string]*tview.TreeNode),
 		roleNodes:        make(map[string]*tview.TreeNode),
 		roles:            make([]*discordgo.Role, 0),
 		rootNode:         tview.NewTreeNode(""),
@@ -51,8 +63,12 @@ func (userTree *UserTree) Clear() {
 	}
 
 	userTree.userNodes = make(map[string]*tview.TreeNode)
+	userTree.userParents = make(map[string]*tview.TreeNode)
 	userTree.roleNodes = make(map[string]*tview.TreeNode)
 	userTree.roles = make([]*discordgo.Role, 0)
+	userTree.offlineNode = nil
+	userTree.guildID = ""
+	userTree.groupChannelID = ""
 
 	userTree.rootNode.ClearChildren()
 }
@@ -66,6 +82,7 @@ func (userTree *UserTree) LoadGroup(channelID string) error {
 		return stateError
 	}
 
+	userTree.groupChannelID = channelID
 	userTree.AddOrUpdateUsers(channel.Recipients)
 
 	userTree.selectFirstNode()
@@ -83,6 +100,17 @@ func (userTree *UserTree) LoadGuild(guildID string) error {
 		return roleLoadError
 	}
 	userTree.roles = guildRoles
+	userTree.guildID = guildID
+
+	if config.GetConfig().GroupOfflineMembers {
+		userTree.offlineNode = tview.NewTreeNode("Offline")
+		userTree.offlineNode.SetExpanded(false)
+		offlineNode := userTree.offlineNode
+		offlineNode.SetSelectedFunc(func() {
+			offlineNode.SetExpanded(!offlineNode.IsExpanded())
+		})
+		userTree.rootNode.AddChild(userTree.offlineNode)
+	}
 
 	userLoadError := userTree.loadGuildMembers(guildID)
 	if userLoadError != nil {
@@ -139,33 +167,72 @@ func (userTree *UserTree) loadGuildRoles(guildID string) ([]*discordgo.Role, err
 }
 
 // AddOrUpdateMember adds the passed member to the tree, unless it is
-// already part of the tree, in that case the nodes name is updated.
+// already part of the tree, in that case the nodes name is updated. If the
+// member has gone offline or changed its roles, the node is moved as well.
 func (userTree *UserTree) AddOrUpdateMember(member *discordgo.Member) {
+	status := getPresenceStatus(userTree.state, userTree.guildID, member.User.ID)
 	nameToUse := discordutil.GetMemberName(member)
-	if config.GetConfig().UseRandomUserColors {
+	roleColor := 0
+	if config.GetConfig().UseRoleColors {
+		roleColor = discordutil.GetRoleColor(member, userTree.roles)
+	}
+	if roleColor != 0 {
+		nameToUse = roleColorToTag(roleColor) + nameToUse
+	} else if config.GetConfig().UseRandomUserColors {
 		nameToUse = "[" + discordutil.GetUserColor(member.User) + "]" + nameToUse
 	}
+	nameToUse = presenceIndicator(status) + nameToUse
 
+	parentNode := userTree.getParentNode(member, status)
 	userNode, contains := userTree.userNodes[member.User.ID]
 	if contains && userNode != nil {
 		userNode.SetText(nameToUse)
+		if userTree.userParents[member.User.ID] != parentNode {
+			userTree.removeNode(userNode)
+			userTree.addUserNode(member.User.ID, userNode, parentNode)
+		}
 		return
 	}
 
 	userNode = tview.NewTreeNode(nameToUse)
 	userTree.userNodes[member.User.ID] = userNode
+	userTree.addUserNode(member.User.ID, userNode, parentNode)
+}
+
+// getParentNode returns the node that a member with the given status
+// belongs to. That's either the offline group, the node of the highest
+// hoisted role of the member or the root node.
+func (userTree *UserTree) getParentNode(member *discordgo.Member, status discordgo.Status) *tview.TreeNode {
+	if userTree.offlineNode != nil && isOffline(status) {
+		return userTree.offlineNode
+	}
 
 	discordutil.SortUserRoles(member.Roles, userTree.roles)
 
 	for _, userRole := range member.Roles {
 		roleNode, exists := userTree.roleNodes[userRole]
 		if exists && roleNode != nil {
-			roleNode.AddChild(userNode)
-			return
+			return roleNode
 		}
 	}
 
-	userTree.rootNode.AddChild(userNode)
+	return userTree.rootNode
+}
+
+// addUserNode adds the node of the given user to the given parent, keeping
+// the offline group at the end.
+func (userTree *UserTree) addUserNode(userID string, userNode, parentNode *tview.TreeNode) {
+	userTree.userParents[userID] = parentNode
+	if parentNode != userTree.rootNode || userTree.offlineNode == nil {
+		parentNode.AddChild(userNode)
+		return
+	}
+
+	children := userTree.rootNode.GetChildren()
+	newChildren := make([]*tview.TreeNode, 0, len(children)+1)
+	newChildren = append(newChildren, children[:len(children)-1]...)
+	newChildren = append(newChildren, userNode, userTree.offlineNode)
+	userTree.rootNode.SetChildren(newChildren)
 }
 
 // AddOrUpdateUser adds a user to the tree, unless the user already exists,
@@ -175,6 +242,7 @@ func (userTree *UserTree) AddOrUpdateUser(user *discordgo.User) {
 	if config.GetConfig().UseRandomUserColors {
 		nameToUse = "[" + discordutil.GetUserColor(user) + "]" + nameToUse
 	}
+	nameToUse = presenceIndicator(getPresenceStatus(userTree.state, "", user.ID)) + nameToUse
 
 	userNode, contains := userTree.userNodes[user.ID]
 	if contains && userNode != nil {
@@ -184,7 +252,36 @@ func (userTree *UserTree) AddOrUpdateUser(user *discordgo.User) {
 
 	userNode = tview.NewTreeNode(nameToUse)
 	userTree.userNodes[user.ID] = userNode
-	userTree.rootNode.AddChild(userNode)
+	userTree.addUserNode(user.ID, userNode, userTree.rootNode)
+}
+
+// UpdatePresence updates the presence shown for the given user. The
+// guildID is the guild that the presence belongs to, which is empty for
+// presences of friends. Users that aren't part of the tree are ignored.
+func (userTree *UserTree) UpdatePresence(guildID, userID string) {
+	if _, contains := userTree.userNodes[userID]; !contains || guildID != userTree.guildID {
+		return
+	}
+
+	if guildID != "" {
+		member, stateError := userTree.state.Member(guildID, userID)
+		if stateError == nil {
+			userTree.AddOrUpdateMember(member)
+		}
+		return
+	}
+
+	channel, stateError := userTree.state.PrivateChannel(userTree.groupChannelID)
+	if stateError != nil {
+		return
+	}
+
+	for _, recipient := range channel.Recipients {
+		if recipient.ID == userID {
+			userTree.AddOrUpdateUser(recipient)
+			break
+		}
+	}
 }
 
 // AddOrUpdateUsers adds users to the tree, unless they already exists, in that
@@ -207,35 +304,42 @@ func (userTree *UserTree) AddOrUpdateMembers(members []*discordgo.Member) {
 func (userTree *UserTree) RemoveMember(member *discordgo.Member) {
 	userNode, contains := userTree.userNodes[member.User.ID]
 	if contains {
-		userTree.rootNode.Walk(func(node, parent *tview.TreeNode) bool {
-			if node == userNode {
-				if len(parent.GetChildren()) == 1 {
-					parent.SetChildren(make([]*tview.TreeNode, 0))
-				} else {
-					indexToDelete := -1
-					for index, child := range parent.GetChildren() {
-						if child == node {
-							indexToDelete = index
-							break
-						}
-					}
+		userTree.removeNode(userNode)
+		delete(userTree.userNodes, member.User.ID)
+		delete(userTree.userParents, member.User.ID)
+	}
+}
 
-					if indexToDelete == 0 {
-						parent.SetChildren(parent.GetChildren()[1:])
-					} else if indexToDelete == len(parent.GetChildren())-1 {
-						parent.SetChildren(parent.GetChildren()[:len(parent.GetChildren())-1])
-					} else {
-						parent.SetChildren(append(parent.GetChildren()[0:indexToDelete],
-							parent.GetChildren()[indexToDelete+1:]...))
+// removeNode removes the given node from its parent.
+func (userTree *UserTree) removeNode(userNode *tview.TreeNode) {
+	userTree.rootNode.Walk(func(node, parent *tview.TreeNode) bool {
+		if node == userNode {
+			if len(parent.GetChildren()) == 1 {
+				parent.SetChildren(make([]*tview.TreeNode, 0))
+			} else {
+				indexToDelete := -1
+				for index, child := range parent.GetChildren() {
+					if child == node {
+						indexToDelete = index
+						break
 					}
 				}
 
-				return false
+				if indexToDelete == 0 {
+					parent.SetChildren(parent.GetChildren()[1:])
+				} else if indexToDelete == len(parent.GetChildren())-1 {
+					parent.SetChildren(parent.GetChildren()[:len(parent.GetChildren())-1])
+				} else {
+					parent.SetChildren(append(parent.GetChildren()[0:indexToDelete],
+						parent.GetChildren()[indexToDelete+1:]...))
+				}
 			}
 
-			return true
-		})
-	}
+			return false
+		}
+
+		return true
+	})
 }
 
 // RemoveMembers finds and removes all passed members from the tree.
@@ -245,7 +349,7 @@ func (userTree *UserTree) RemoveMembers(members []*discordgo.Member) {
 	}
 }
 
-//SetInputCapture delegates to tviews SetInputCapture
+// SetInputCapture delegates to tviews SetInputCapture
 func (userTree *UserTree) SetInputCapture(capture func(event *tcell.EventKey) *tcell.EventKey) {
 	userTree.internalTreeView.SetInputCapture(capture)
 }
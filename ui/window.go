@@ -2,10 +2,13 @@ package ui
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Bios-Marcel/discordemojimap"
@@ -17,6 +20,7 @@ import (
 	"github.com/Bios-Marcel/cordless/config"
 	"github.com/Bios-Marcel/cordless/discordutil"
 	"github.com/Bios-Marcel/cordless/maths"
+	"github.com/Bios-Marcel/cordless/mentions"
 	"github.com/Bios-Marcel/cordless/readstate"
 	"github.com/Bios-Marcel/cordless/scripting"
 	"github.com/Bios-Marcel/cordless/scripting/js"
@@ -34,6 +38,10 @@ const (
 	guildPageName    = "Guilds"
 	privatePageName  = "Private"
 	userInactiveTime = 10 * time.Second
+
+	// bulkAckInterval is the time waited between acknowledgements when
+	// marking many channels as read, so that the rate limits aren't hit.
+	bulkAckInterval = 500 * time.Millisecond
 )
 
 var (
@@ -42,6 +50,8 @@ var (
 	// to use the emoji as a custom emoji, since there can be clashes with for
 	// example :joy:, which is a default emoji code.
 	emojiRegex = regexp.MustCompile("(m?)(^|[^<]):!?.+?:")
+
+	errAlreadyMarkingAsRead = errors.New("channels are already being marked as read, please wait until that's done")
 )
 
 // Window is basically the whole application, as it contains all the
@@ -61,11 +71,33 @@ type Window struct {
 	privateList *PrivateChatList
 
 	chatArea         *tview.Flex
+	tabBar           *TabBar
+	chatPanes        *tview.Flex
 	chatView         *ChatView
 	messageContainer tview.Primitive
+	typingStatus     *tview.TextView
 	messageInput     *Editor
 
+	// inactivePane is the pane of the split view that isn't active. The
+	// active pane is represented by chatView and selectedChannel, which is
+	// what messages are sent to. inactivePane is nil if the view isn't split.
+	inactivePane *chatPane
+	// paneMutex guards chatView, selectedChannel and inactivePane. They are
+	// only changed on the UI goroutine, but the event handlers read them
+	// through getPanes.
+	paneMutex *sync.RWMutex
+	// primaryPaneActive is false while the pane that has been added by
+	// splitting the view is active. The tabs only belong to the primary pane.
+	primaryPaneActive bool
+
+	// typingUsers contains the users that are currently typing in any
+	// channel. Only the ones in the selected channel are shown.
+	typingUsers *typingUsers
+
 	editingMessageID *string
+	// editHistory contains the previous versions of all messages that have
+	// been edited since starting the application.
+	editHistory *editHistory
 
 	userList *UserTree
 
@@ -79,6 +111,12 @@ type Window struct {
 	previousChannelNode *tview.TreeNode
 	selectedChannel     *discordgo.Channel
 	previousChannel     *discordgo.Channel
+	// messageToSelect is the ID of a message that will be selected once the
+	// channel that is currently being loaded has been loaded.
+	messageToSelect string
+	// recentQuickSwitches contains the keys of the items recently picked in
+	// the quick switcher, most recent first.
+	recentQuickSwitches []string
 
 	jsEngine scripting.Engine
 
@@ -89,6 +127,11 @@ type Window struct {
 	userActive      bool
 	userActiveTimer *time.Timer
 
+	// markingAsRead is true while channels are being marked as read in the
+	// background. Only one such run is allowed at a time.
+	markingAsRead      bool
+	markingAsReadMutex *sync.Mutex
+
 	doRestart chan bool
 }
 
@@ -102,6 +145,11 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 		app:             app,
 		jsEngine:        js.New(),
 		userActiveTimer: time.NewTimer(userInactiveTime),
+		editHistory:     newEditHistory(),
+		typingUsers:     newTypingUsers(),
+
+		markingAsReadMutex: &sync.Mutex{},
+		paneMutex:          &sync.RWMutex{},
 	}
 
 	go func() {
@@ -207,6 +255,7 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 	window.guildList = guildList
 
 	window.registerGuildHandlers()
+	window.registerGuildFolderHandler()
 	window.registerGuildMemberHandlers()
 
 	if config.GetConfig().MouseEnabled {
@@ -230,6 +279,8 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 	window.privateList = NewPrivateChatList(window.session.State)
 	window.privateList.Load()
 	window.registerPrivateChatsHandler()
+	window.registerPresenceHandler()
+	window.registerUserGuildSettingsHandler()
 
 	if config.GetConfig().MouseEnabled {
 		privatePage := tview.NewFlex().SetDirection(tview.FlexRow)
@@ -308,52 +359,30 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 	window.chatArea = tview.NewFlex().
 		SetDirection(tview.FlexRow)
 
-	window.chatView = NewChatView(window.session.State, window.session.State.User.ID)
-	window.chatView.SetOnMessageAction(func(message *discordgo.Message, event *tcell.EventKey) *tcell.EventKey {
-		if shortcuts.QuoteSelectedMessage.Equals(event) {
-			window.insertQuoteOfMessage(message)
-			return nil
-		}
-
-		if shortcuts.ReplySelectedMessage.Equals(event) {
-			window.messageInput.SetText("@" + message.Author.Username + "#" + message.Author.Discriminator + " " + window.messageInput.GetText())
-			app.SetFocus(window.messageInput.GetPrimitive())
-			return nil
-		}
-
-		if shortcuts.CopySelectedMessageLink.Equals(event) {
-			copyError := clipboard.WriteAll(fmt.Sprintf("<https://discordapp.com/channels/@me/%s/%s>", message.ChannelID, message.ID))
-			if copyError != nil {
-				window.ShowErrorDialog(fmt.Sprintf("Error copying message link: %s", copyError.Error()))
-			}
-			return nil
-		}
+	window.tabBar = NewTabBar()
+	window.tabBar.SetOnTabSelect(window.switchToTab)
 
-		if shortcuts.DeleteSelectedMessage.Equals(event) {
-			if message.Author.ID == window.session.State.User.ID {
-				window.askForMessageDeletion(message.ID, true)
-			}
-			return nil
-		}
+	window.chatView = NewChatView(window.session.State, window.session.State.User.ID)
+	window.chatPanes = tview.NewFlex()
+	if !config.GetConfig().SplitViewSideBySide {
+		window.chatPanes.SetDirection(tview.FlexRow)
+	}
+	window.primaryPaneActive = true
+	window.chatPanes.AddItem(window.chatView.GetPrimitive(), 0, 1, false)
+	window.messageContainer = window.chatPanes
 
-		if shortcuts.EditSelectedMessage.Equals(event) {
-			window.startEditingMessage(message)
-			return nil
-		}
+	window.typingStatus = tview.NewTextView()
+	window.typingStatus.SetDynamicColors(true)
+	window.typingStatus.SetVisible(false)
+	window.registerTypingHandler()
 
-		if shortcuts.CopySelectedMessage.Equals(event) {
-			copyError := clipboard.WriteAll(message.ContentWithMentionsReplaced())
-			if copyError != nil {
-				window.ShowErrorDialog(fmt.Sprintf("Error copying message: %s", copyError.Error()))
-			}
-			return nil
+	window.messageInput = NewEditor()
+	window.messageInput.SetTypingHandler(func() {
+		if config.GetConfig().SendTypingNotifications &&
+			window.selectedChannel != nil && window.editingMessageID == nil {
+			go window.session.ChannelTyping(window.selectedChannel.ID)
 		}
-
-		return event
 	})
-	window.messageContainer = window.chatView.GetPrimitive()
-
-	window.messageInput = NewEditor()
 	window.messageInput.internalTextView.SetIndicateOverflow(true)
 	window.messageInput.SetOnHeightChangeRequest(func(height int) {
 		_, _, _, chatViewHeight := window.chatView.internalTextView.GetRect()
@@ -505,6 +534,7 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 
 	window.registerMessageEventHandler(messageInputChan, messageEditChan, messageDeleteChan, messageBulkDeleteChan)
 	window.startMessageHandlerRoutines(messageInputChan, messageEditChan, messageDeleteChan, messageBulkDeleteChan)
+	window.startTimeRefreshRoutine()
 
 	window.userList = NewUserTree(window.session.State)
 
@@ -522,13 +552,36 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 			window.app, window.messageInput.internalTextView))
 		window.privateList.SetInputCapture(tviewutil.CreateFocusTextViewOnTypeInputHandler(
 			window.app, window.messageInput.internalTextView))
-		window.chatView.internalTextView.SetInputCapture(tviewutil.CreateFocusTextViewOnTypeInputHandler(
-			window.app, window.messageInput.internalTextView))
 	}
 
 	//Guild Container arrow key navigation. Please end my life.
 	oldGuildListHandler := guildList.GetInputCapture()
 	newGuildHandler := func(event *tcell.EventKey) *tcell.EventKey {
+		if shortcuts.ToggleMute.Equals(event) {
+			if node := guildList.GetCurrentNode(); node != nil {
+				if guildID, isGuild := node.GetReference().(string); isGuild {
+					window.toggleGuildMute(guildID)
+				}
+			}
+			return nil
+		}
+
+		if shortcuts.MarkGuildAsRead.Equals(event) {
+			if node := guildList.GetCurrentNode(); node != nil {
+				var markError error
+				switch reference := node.GetReference().(type) {
+				case string:
+					_, markError = window.MarkGuildsAsRead(reference)
+				case *discordutil.GuildFolder:
+					_, markError = window.MarkGuildsAsRead(reference.GuildIDs...)
+				}
+				if markError != nil {
+					window.ShowErrorDialog(markError.Error())
+				}
+			}
+			return nil
+		}
+
 		if event.Modifiers() == tcell.ModAlt {
 			if event.Key() == tcell.KeyDown || event.Key() == tcell.KeyUp {
 				window.app.SetFocus(window.channelTree)
@@ -569,6 +622,13 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 	//Channel Container arrow key navigation. Please end my life.
 	oldChannelListHandler := channelTree.GetInputCapture()
 	newChannelListHandler := func(event *tcell.EventKey) *tcell.EventKey {
+		if shortcuts.ToggleMute.Equals(event) {
+			if node := channelTree.GetCurrentNode(); node != nil {
+				window.toggleChannelMuteOfNode(node)
+			}
+			return nil
+		}
+
 		if event.Modifiers() == tcell.ModAlt {
 			if event.Key() == tcell.KeyDown || event.Key() == tcell.KeyUp {
 				window.app.SetFocus(window.guildList)
@@ -614,65 +674,7 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 		})
 	}
 
-	//Chatview arrow key navigation. Please end my life.
-	oldChatViewHandler := window.chatView.internalTextView.GetInputCapture()
-	newChatViewHandler := func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Modifiers() == tcell.ModAlt {
-			if event.Key() == tcell.KeyDown {
-				window.app.SetFocus(window.messageInput.GetPrimitive())
-				return nil
-			}
-
-			if event.Key() == tcell.KeyUp {
-				if window.commandMode {
-					window.app.SetFocus(window.commandView.commandInput.internalTextView)
-				} else {
-					window.app.SetFocus(window.messageInput.GetPrimitive())
-				}
-				return nil
-			}
-
-			if event.Key() == tcell.KeyLeft {
-				if window.leftArea.GetCurrentPage() == guildPageName {
-					window.app.SetFocus(window.guildList)
-					return nil
-				} else if window.leftArea.GetCurrentPage() == guildPageName {
-					window.app.SetFocus(window.privateList.internalTreeView)
-					return nil
-				}
-			}
-
-			if event.Key() == tcell.KeyRight {
-				if window.userList.internalTreeView.IsVisible() {
-					window.app.SetFocus(window.userList.internalTreeView)
-				} else {
-					if window.leftArea.GetCurrentPage() == guildPageName {
-						window.app.SetFocus(window.guildList)
-						return nil
-					} else if window.leftArea.GetCurrentPage() == guildPageName {
-						window.app.SetFocus(window.privateList.internalTreeView)
-						return nil
-					}
-				}
-				return nil
-			}
-		}
-
-		return event
-	}
-
-	if oldChatViewHandler == nil {
-		window.chatView.internalTextView.SetInputCapture(newChatViewHandler)
-	} else {
-		window.chatView.internalTextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			handledEvent := newChatViewHandler(event)
-			if handledEvent != nil {
-				return oldChatViewHandler(event)
-			}
-
-			return event
-		})
-	}
+	window.initChatView(window.chatView)
 
 	//User Container arrow key navigation. Please end my life.
 	oldUserListHandler := window.userList.internalTreeView.GetInputCapture()
@@ -713,6 +715,13 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 	//Private Container arrow key navigation. Please end my life.
 	oldPrivateListHandler := window.privateList.internalTreeView.GetInputCapture()
 	newPrivateListHandler := func(event *tcell.EventKey) *tcell.EventKey {
+		if shortcuts.ToggleMute.Equals(event) {
+			if node := window.privateList.GetComponent().GetCurrentNode(); node != nil {
+				window.toggleChannelMuteOfNode(node)
+			}
+			return nil
+		}
+
 		if event.Modifiers() == tcell.ModAlt {
 			if event.Key() == tcell.KeyLeft {
 				if window.userList.internalTreeView.IsVisible() {
@@ -747,6 +756,14 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 
 	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.MessageAck) {
 		if readstate.UpdateReadLocal(event.ChannelID, event.MessageID) {
+			//Acknowledgements sent by cordless itself are applied locally
+			//beforehand, therefore this one must stem from a different client.
+			if window.selectedChannel != nil && window.selectedChannel.ID == event.ChannelID {
+				window.app.QueueUpdateDraw(func() {
+					window.chatView.ClearUnreadMarker()
+				})
+			}
+
 			channel, stateError := s.State.Channel(event.ChannelID)
 			if stateError == nil && event.MessageID == channel.LastMessageID {
 				if channel.GuildID == "" {
@@ -754,12 +771,10 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 				} else {
 					if window.selectedGuild != nil && channel.GuildID == window.selectedGuild.ID {
 						window.channelTree.MarkChannelAsRead(channel.ID)
+						window.guildList.UpdateGuildBadge(channel.GuildID)
 					} else {
-						for _, guildNode := range window.guildList.GetRoot().GetChildren() {
-							if guildNode.GetReference() == channel.GuildID {
-								window.updateServerReadStatus(channel.GuildID, guildNode, false)
-								break
-							}
+						if guildNode := window.guildList.GetGuildNode(channel.GuildID); guildNode != nil {
+							window.updateServerReadStatus(channel.GuildID, guildNode, false)
 						}
 					}
 				}
@@ -897,7 +912,9 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 		window.messageInput.mentionHideHandler()
 	})
 
+	window.chatArea.AddItem(window.tabBar, 1, 0, false)
 	window.chatArea.AddItem(window.messageContainer, 0, 1, false)
+	window.chatArea.AddItem(window.typingStatus, 1, 0, false)
 	window.chatArea.AddItem(mentionWindow, 2, 2, true)
 	window.chatArea.AddItem(window.messageInput.GetPrimitive(), window.messageInput.GetRequestedHeight(), 0, false)
 
@@ -913,9 +930,157 @@ func NewWindow(doRestart chan bool, app *tview.Application, session *discordgo.S
 
 	window.registerMouseFocusListeners()
 
+	window.restoreTabs()
+
 	return window, nil
 }
 
+// handleMessageAction handles the shortcuts that the user can apply to the
+// selected message of a ChatView.
+func (window *Window) handleMessageAction(message *discordgo.Message, event *tcell.EventKey) *tcell.EventKey {
+	if shortcuts.QuoteSelectedMessage.Equals(event) {
+		window.insertQuoteOfMessage(message)
+		return nil
+	}
+
+	if shortcuts.ReplySelectedMessage.Equals(event) {
+		window.messageInput.SetText("@" + message.Author.Username + "#" + message.Author.Discriminator + " " + window.messageInput.GetText())
+		window.app.SetFocus(window.messageInput.GetPrimitive())
+		return nil
+	}
+
+	if shortcuts.CopySelectedMessageLink.Equals(event) {
+		copyError := clipboard.WriteAll(fmt.Sprintf("<https://discordapp.com/channels/@me/%s/%s>", message.ChannelID, message.ID))
+		if copyError != nil {
+			window.ShowErrorDialog(fmt.Sprintf("Error copying message link: %s", copyError.Error()))
+		}
+		return nil
+	}
+
+	if shortcuts.DeleteSelectedMessage.Equals(event) {
+		if message.Author.ID == window.session.State.User.ID {
+			window.askForMessageDeletion(message.ID, true)
+		}
+		return nil
+	}
+
+	if shortcuts.EditSelectedMessage.Equals(event) {
+		window.startEditingMessage(message)
+		return nil
+	}
+
+	if shortcuts.ShowEditHistory.Equals(event) {
+		window.showEditHistory(message)
+		return nil
+	}
+
+	if shortcuts.CopySelectedMessage.Equals(event) {
+		copyError := clipboard.WriteAll(message.ContentWithMentionsReplaced())
+		if copyError != nil {
+			window.ShowErrorDialog(fmt.Sprintf("Error copying message: %s", copyError.Error()))
+		}
+		return nil
+	}
+
+	return event
+}
+
+// initChatView registers the handlers that every ChatView requires, no matter
+// which pane it is shown in.
+func (window *Window) initChatView(chatView *ChatView) {
+	chatView.SetOnMessageAction(window.handleMessageAction)
+	chatView.SetOnOlderMessagesRequest(func() {
+		window.loadOlderMessages(chatView)
+	})
+	chatView.SetOnLinksRequest(window.showLinkPicker)
+
+	if config.GetConfig().OnTypeInListBehaviour == config.FocusMessageInputOnTypeInList {
+		chatView.internalTextView.SetInputCapture(tviewutil.CreateFocusTextViewOnTypeInputHandler(
+			window.app, window.messageInput.internalTextView))
+	}
+
+	//Chatview arrow key navigation. Please end my life.
+	oldChatViewHandler := chatView.internalTextView.GetInputCapture()
+	newChatViewHandler := func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Modifiers() == tcell.ModAlt {
+			if event.Key() == tcell.KeyDown {
+				window.app.SetFocus(window.messageInput.GetPrimitive())
+				return nil
+			}
+
+			if event.Key() == tcell.KeyUp {
+				if window.commandMode {
+					window.app.SetFocus(window.commandView.commandInput.internalTextView)
+				} else {
+					window.app.SetFocus(window.messageInput.GetPrimitive())
+				}
+				return nil
+			}
+
+			if event.Key() == tcell.KeyLeft {
+				if window.leftArea.GetCurrentPage() == guildPageName {
+					window.app.SetFocus(window.guildList)
+					return nil
+				} else if window.leftArea.GetCurrentPage() == guildPageName {
+					window.app.SetFocus(window.privateList.internalTreeView)
+					return nil
+				}
+			}
+
+			if event.Key() == tcell.KeyRight {
+				if window.userList.internalTreeView.IsVisible() {
+					window.app.SetFocus(window.userList.internalTreeView)
+				} else {
+					if window.leftArea.GetCurrentPage() == guildPageName {
+						window.app.SetFocus(window.guildList)
+						return nil
+					} else if window.leftArea.GetCurrentPage() == guildPageName {
+						window.app.SetFocus(window.privateList.internalTreeView)
+						return nil
+					}
+				}
+				return nil
+			}
+		}
+
+		return event
+	}
+
+	if oldChatViewHandler == nil {
+		chatView.internalTextView.SetInputCapture(newChatViewHandler)
+	} else {
+		chatView.internalTextView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			handledEvent := newChatViewHandler(event)
+			if handledEvent != nil {
+				return oldChatViewHandler(event)
+			}
+
+			return event
+		})
+	}
+
+	chatView.internalTextView.SetMouseHandler(func(event *tcell.EventMouse) bool {
+		if event.Buttons() == tcell.Button1 {
+			if chatView != window.chatView {
+				window.switchActivePane()
+			}
+			window.app.SetFocus(chatView.internalTextView)
+		} else if event.Buttons() == tcell.WheelDown {
+			chatView.internalTextView.ScrollDown()
+		} else if event.Buttons() == tcell.WheelUp {
+			if chatView.IsScrolledToStart() {
+				chatView.requestOlderMessages()
+			} else {
+				chatView.internalTextView.ScrollUp()
+			}
+		} else {
+			return false
+		}
+
+		return true
+	})
+}
+
 func (window *Window) loadPrivateChannel(channel *discordgo.Channel) {
 	window.LoadChannel(channel)
 	window.RefreshLayout()
@@ -1152,6 +1317,12 @@ func (window *Window) updateServerReadStatus(guildID string, guildNode *tview.Tr
 			guildNode.SetColor(tview.Styles.PrimaryTextColor)
 		}
 	}
+
+	//The guild list calls this during its own creation.
+	if window.guildList != nil {
+		window.guildList.UpdateGuildBadge(guildID)
+		window.guildList.UpdateFolderReadStatus(guildID)
+	}
 }
 
 // prepareMessage prepares a message for being sent to the discord API.
@@ -1354,40 +1525,602 @@ func (window *Window) ShowDialog(color tcell.Color, text string, buttonHandler f
 	window.rootContainer.ResizeItem(window.dialogReplacement, height+2, 0)
 }
 
-func (window *Window) registerMouseFocusListeners() {
-	window.chatView.internalTextView.SetMouseHandler(func(event *tcell.EventMouse) bool {
-		if event.Buttons() == tcell.Button1 {
-			window.app.SetFocus(window.chatView.internalTextView)
-		} else if event.Buttons() == tcell.WheelDown {
-			window.chatView.internalTextView.ScrollDown()
-		} else if event.Buttons() == tcell.WheelUp {
-			window.chatView.internalTextView.ScrollUp()
-		} else {
-			return false
-		}
-
-		return true
-	})
+// showLinkPicker shows an overlay that lists all links of the given
+// messages, allowing the user to open, copy or download them.
+func (window *Window) showLinkPicker(messages []*discordgo.Message) {
+	links := collectLinks(messages)
+	if len(links) == 0 {
+		window.ShowDialog(config.GetTheme().PrimitiveBackgroundColor,
+			"There are no links in the selected or visible messages.", func(_ string) {}, "Okay")
+		return
+	}
 
-	window.guildList.SetMouseHandler(func(event *tcell.EventMouse) bool {
-		if event.Buttons() == tcell.Button1 {
-			window.app.SetFocus(window.guildList)
-			return true
+	linkPicker := NewLinkPicker(window.app, links)
+	doClose := func() {
+		window.app.SetRoot(window.rootContainer, true)
+		window.currentContainer = window.rootContainer
+		window.app.SetFocus(window.chatView.internalTextView)
+	}
+	linkPicker.SetOnClose(doClose)
+	linkPicker.SetOnOpen(func(url string) {
+		doClose()
+		openError := openLink(url)
+		if openError != nil {
+			window.ShowErrorDialog(fmt.Sprintf("Error opening link: %s", openError.Error()))
 		}
-
-		return false
 	})
-	window.channelTree.SetMouseHandler(func(event *tcell.EventMouse) bool {
-		if event.Buttons() == tcell.Button1 {
-			window.app.SetFocus(window.channelTree)
-
-			return true
+	linkPicker.SetOnCopy(func(url string) {
+		doClose()
+		copyError := clipboard.WriteAll(url)
+		if copyError != nil {
+			window.ShowErrorDialog(fmt.Sprintf("Error copying link: %s", copyError.Error()))
 		}
-
-		return false
+	})
+	linkPicker.SetOnDownload(func(url, directory string) {
+		doClose()
+		go func() {
+			filePath, downloadError := downloadLink(url, directory)
+			window.QueueUpdateDrawSynchronized(func() {
+				if downloadError != nil {
+					window.ShowErrorDialog(fmt.Sprintf("Error downloading link: %s", downloadError.Error()))
+				} else {
+					window.ShowDialog(config.GetTheme().PrimitiveBackgroundColor,
+						fmt.Sprintf("Downloaded link to '%s'.", filePath), func(_ string) {}, "Okay")
+				}
+			})
+		}()
 	})
 
-	window.userList.internalTreeView.SetMouseHandler(func(event *tcell.EventMouse) bool {
+	window.app.SetRoot(linkPicker, true)
+	window.app.SetFocus(linkPicker.GetFocusTarget())
+	window.currentContainer = linkPicker
+}
+
+// showEditHistory shows an overlay containing all versions of the given
+// message that have been seen since starting the application. Each version
+// is compared to its predecessor word by word.
+func (window *Window) showEditHistory(message *discordgo.Message) {
+	versions := window.editHistory.getVersions(message)
+	if len(versions) == 0 {
+		window.ShowDialog(config.GetTheme().PrimitiveBackgroundColor,
+			"No edits of this message have been seen since starting cordless.", func(_ string) {}, "Okay")
+		return
+	}
+
+	var historyText strings.Builder
+	for index, version := range versions {
+		if index == 0 {
+			historyText.WriteString("[::b]Original")
+		} else {
+			historyText.WriteString("\n\n[::b]Edit " + strconv.Itoa(index))
+		}
+		versionTime, parseError := version.timestamp.Parse()
+		if parseError == nil {
+			historyText.WriteString(" - " + times.TimeToLocalString(&versionTime))
+		}
+		historyText.WriteString("[::-]\n")
+
+		if index == 0 {
+			historyText.WriteString(tview.Escape(version.content))
+		} else {
+			historyText.WriteString(diffWords(versions[index-1].content, version.content))
+		}
+	}
+
+	historyView := tview.NewTextView()
+	historyView.SetDynamicColors(true)
+	historyView.SetWordWrap(true)
+	historyView.SetBorder(true)
+	historyView.SetTitle("Edit history - Esc to close")
+	historyView.SetText(historyText.String())
+	historyView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			window.app.SetRoot(window.rootContainer, true)
+			window.currentContainer = window.rootContainer
+			window.app.SetFocus(window.chatView.internalTextView)
+			return nil
+		}
+
+		return event
+	})
+
+	window.app.SetRoot(historyView, true)
+	window.app.SetFocus(historyView)
+	window.currentContainer = historyView
+}
+
+// showMentionsInbox shows an overlay that lists all mentions of the current
+// user that haven't been marked as done yet.
+func (window *Window) showMentionsInbox() {
+	mentionsInbox := NewMentionsInbox(mentions.GetMentions())
+	doClose := func() {
+		window.app.SetRoot(window.rootContainer, true)
+		window.currentContainer = window.rootContainer
+		window.app.SetFocus(window.chatView.internalTextView)
+	}
+	mentionsInbox.SetOnClose(doClose)
+	mentionsInbox.SetOnMarkDone(func(mention *mentions.Mention) {
+		if mentions.MarkDone(mention.MessageID) {
+			window.persistMentions()
+		}
+	})
+	mentionsInbox.SetOnJump(func(mention *mentions.Mention) {
+		doClose()
+		window.jumpToMention(mention)
+	})
+
+	window.app.SetRoot(mentionsInbox, true)
+	window.app.SetFocus(mentionsInbox.GetFocusTarget())
+	window.currentContainer = mentionsInbox
+}
+
+// showQuickSwitcher shows an overlay that allows jumping to any guild or
+// channel by searching for its name.
+func (window *Window) showQuickSwitcher() {
+	quickSwitcher := NewQuickSwitcher(window.getQuickSwitchItems(), window.recentQuickSwitches)
+	doClose := func() {
+		window.app.SetRoot(window.rootContainer, true)
+		window.currentContainer = window.rootContainer
+		window.app.SetFocus(window.chatView.internalTextView)
+	}
+	quickSwitcher.SetOnClose(doClose)
+	quickSwitcher.SetOnSelect(func(item *quickSwitchItem) {
+		doClose()
+		window.recentQuickSwitches = addRecentQuickSwitch(window.recentQuickSwitches, item.key())
+
+		var navigateError error
+		if item.channelID == "" {
+			navigateError = window.navigateToGuild(item.guildID)
+		} else {
+			channel, stateError := window.session.State.Channel(item.channelID)
+			if stateError != nil {
+				navigateError = stateError
+			} else {
+				navigateError = window.navigateToChannel(channel)
+			}
+		}
+
+		if navigateError != nil {
+			window.ShowErrorDialog(navigateError.Error())
+		}
+	})
+
+	window.app.SetRoot(quickSwitcher, true)
+	window.app.SetFocus(quickSwitcher.GetFocusTarget())
+	window.currentContainer = quickSwitcher
+}
+
+// getQuickSwitchItems returns all guilds, readable text channels and private
+// channels. Guilds are ordered like in the guild list, each followed by its
+// channels. Items count as mentioned while they contain unread mentions.
+func (window *Window) getQuickSwitchItems() []*quickSwitchItem {
+	state := window.session.State
+	var items []*quickSwitchItem
+	window.guildList.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		guildID, isGuild := node.GetReference().(string)
+		if !isGuild {
+			return true
+		}
+
+		guild, stateError := state.Guild(guildID)
+		if stateError != nil {
+			return true
+		}
+
+		_, guildMentions := readstate.GetGuildCounts(guild.ID)
+		items = append(items, &quickSwitchItem{
+			guildID:   guild.ID,
+			text:      guild.Name,
+			unread:    !readstate.HasGuildBeenRead(guild.ID),
+			mentioned: guildMentions > 0,
+		})
+
+		for _, channel := range guild.Channels {
+			if channel.Type != discordgo.ChannelTypeGuildText ||
+				!discordutil.HasReadMessagesPermission(channel.ID, state) {
+				continue
+			}
+
+			items = append(items, &quickSwitchItem{
+				guildID:   guild.ID,
+				channelID: channel.ID,
+				text:      "#" + channel.Name + " - " + guild.Name,
+				unread:    !readstate.IsChannelMuted(channel) && !readstate.HasBeenRead(channel, channel.LastMessageID),
+				mentioned: readstate.GetMentionCount(channel.ID) > 0,
+			})
+		}
+
+		return true
+	})
+
+	for _, channel := range state.PrivateChannels {
+		items = append(items, &quickSwitchItem{
+			channelID: channel.ID,
+			text:      discordutil.GetPrivateChannelName(channel),
+			unread:    !readstate.IsChannelMuted(channel) && !readstate.HasBeenRead(channel, channel.LastMessageID),
+			mentioned: readstate.GetMentionCount(channel.ID) > 0,
+		})
+	}
+
+	return items
+}
+
+// jumpToNextUnread loads the next channel that contains unread messages or,
+// if mentionsOnly is set, unread mentions. The channels of the selected
+// guild are searched first, followed by the other guilds in the order of
+// the guild list and finally the private chats. Muted guilds and channels
+// are skipped.
+func (window *Window) jumpToNextUnread(mentionsOnly bool) {
+	state := window.session.State
+	var guildIDs []string
+	if window.selectedGuild != nil {
+		guildIDs = append(guildIDs, window.selectedGuild.ID)
+	}
+	window.guildList.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		guildID, isGuild := node.GetReference().(string)
+		if isGuild && (window.selectedGuild == nil || guildID != window.selectedGuild.ID) {
+			guildIDs = append(guildIDs, guildID)
+		}
+		return true
+	})
+
+	var channels []*discordgo.Channel
+	for _, guildID := range guildIDs {
+		if readstate.IsGuildMuted(guildID) {
+			continue
+		}
+
+		guild, stateError := state.Guild(guildID)
+		if stateError == nil {
+			channels = append(channels, getChannelsInTreeOrder(state, guild)...)
+		}
+	}
+
+	for _, node := range window.privateList.chatsNode.GetChildren() {
+		channelID, ok := node.GetReference().(string)
+		if !ok {
+			continue
+		}
+
+		channel, stateError := state.Channel(channelID)
+		if stateError == nil {
+			channels = append(channels, channel)
+		}
+	}
+
+	var currentChannelID string
+	if window.selectedChannel != nil {
+		currentChannelID = window.selectedChannel.ID
+	}
+
+	nextChannel := findNextUnreadChannel(channels, currentChannelID, mentionsOnly)
+	if nextChannel == nil {
+		if mentionsOnly {
+			fmt.Fprintln(window.commandView, "There are no unread mentions.")
+		} else {
+			fmt.Fprintln(window.commandView, "There are no unread channels.")
+		}
+		return
+	}
+
+	if navigateError := window.navigateToChannel(nextChannel); navigateError != nil {
+		window.ShowErrorDialog(navigateError.Error())
+	}
+}
+
+// SetGuildMuted mutes or unmutes the guild with the given ID. Mutes expire
+// after the given duration, unless the duration is zero.
+func (window *Window) SetGuildMuted(guildID string, muted bool, duration time.Duration) error {
+	settings, muteError := discordutil.SetGuildMuted(window.session, guildID, muted, duration)
+	if muteError != nil {
+		return muteError
+	}
+
+	window.applyMute(guildID, settings, muted, duration)
+	return nil
+}
+
+// SetChannelMuted mutes or unmutes the given channel, category or private
+// channel. Mutes expire after the given duration, unless the duration is
+// zero.
+func (window *Window) SetChannelMuted(channel *discordgo.Channel, muted bool, duration time.Duration) error {
+	settings, muteError := discordutil.SetChannelMuted(window.session, channel, muted, duration)
+	if muteError != nil {
+		return muteError
+	}
+
+	window.applyMute(channel.ID, settings, muted, duration)
+	return nil
+}
+
+// applyMute applies the settings returned by discord after muting or
+// unmuting a guild or channel, so that the unread logic respects them
+// immediately, and updates the trees.
+func (window *Window) applyMute(id string, settings *discordgo.UserGuildSettings, muted bool, duration time.Duration) {
+	readstate.UpdateGuildSettings(settings)
+
+	var endTime time.Time
+	if muted {
+		endTime = discordutil.GetMuteEndTime(time.Now(), duration)
+	}
+	readstate.SetMuteEndTime(id, endTime)
+	if !endTime.IsZero() {
+		time.AfterFunc(duration, func() {
+			window.app.QueueUpdateDraw(window.refreshMuteStates)
+		})
+	}
+
+	window.app.QueueUpdateDraw(window.refreshMuteStates)
+}
+
+// refreshMuteStates updates the texts and colours of all guilds and
+// channels, since muting or unmuting changes whether they are considered
+// read.
+func (window *Window) refreshMuteStates() {
+	window.channelTree.RefreshChannelStates()
+	window.privateList.RefreshChannelStates()
+	window.guildList.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		if guildID, isGuild := node.GetReference().(string); isGuild {
+			window.updateServerReadStatus(guildID, node,
+				window.selectedGuild != nil && window.selectedGuild.ID == guildID)
+		}
+		return true
+	})
+}
+
+// toggleGuildMute unmutes the given guild if it's muted. Otherwise the user
+// is asked for how long the guild should be muted.
+func (window *Window) toggleGuildMute(guildID string) {
+	window.toggleMute(readstate.IsGuildMuted(guildID), func(muted bool, duration time.Duration) error {
+		return window.SetGuildMuted(guildID, muted, duration)
+	})
+}
+
+// toggleChannelMuteOfNode unmutes the channel referenced by the given node
+// if it's muted. Otherwise the user is asked for how long the channel
+// should be muted. Nodes that don't reference a channel are ignored.
+func (window *Window) toggleChannelMuteOfNode(node *tview.TreeNode) {
+	channelID, ok := node.GetReference().(string)
+	if !ok {
+		return
+	}
+
+	//Friends are referenced by their user ID.
+	channel, stateError := window.session.State.Channel(channelID)
+	if stateError != nil {
+		return
+	}
+
+	window.toggleMute(readstate.IsChannelMuted(channel), func(muted bool, duration time.Duration) error {
+		return window.SetChannelMuted(channel, muted, duration)
+	})
+}
+
+func (window *Window) toggleMute(isMuted bool, setMuted func(muted bool, duration time.Duration) error) {
+	applyAsync := func(muted bool, duration time.Duration) {
+		go func() {
+			if muteError := setMuted(muted, duration); muteError != nil {
+				window.app.QueueUpdateDraw(func() {
+					window.ShowErrorDialog(muteError.Error())
+				})
+			}
+		}()
+	}
+
+	if isMuted {
+		applyAsync(false, 0)
+		return
+	}
+
+	window.ShowDialog(config.GetTheme().PrimitiveBackgroundColor, "For how long do you want to mute?",
+		func(button string) {
+			if duration, isDuration := muteDurations[button]; isDuration {
+				applyAsync(true, duration)
+			}
+		}, muteDurationButtons...)
+}
+
+// MarkGuildsAsRead acknowledges all readable channels of the given guilds
+// in the background. The amount of channels that are acknowledged is
+// returned. If channels are already being marked as read, an error is
+// returned instead.
+func (window *Window) MarkGuildsAsRead(guildIDs ...string) (int, error) {
+	var channels []*discordgo.Channel
+	for _, guildID := range guildIDs {
+		channels = append(channels, window.getChannelsToAck(guildID)...)
+	}
+
+	return len(channels), window.markChannelsAsRead(channels)
+}
+
+// MarkAllAsRead acknowledges all readable channels of all guilds and all
+// private channels in the background. The amount of channels that are
+// acknowledged is returned. If channels are already being marked as read,
+// an error is returned instead.
+func (window *Window) MarkAllAsRead() (int, error) {
+	var channels []*discordgo.Channel
+	for _, guild := range window.session.State.Guilds {
+		channels = append(channels, window.getChannelsToAck(guild.ID)...)
+	}
+	for _, channel := range window.session.State.PrivateChannels {
+		if readstate.NeedsAck(channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return len(channels), window.markChannelsAsRead(channels)
+}
+
+// getChannelsToAck returns all readable text channels of the given guild
+// that haven't been acknowledged yet.
+func (window *Window) getChannelsToAck(guildID string) []*discordgo.Channel {
+	guild, stateError := window.session.State.Guild(guildID)
+	if stateError != nil {
+		return nil
+	}
+
+	var channels []*discordgo.Channel
+	for _, channel := range guild.Channels {
+		if channel.Type == discordgo.ChannelTypeGuildText &&
+			discordutil.HasReadMessagesPermission(channel.ID, window.session.State) &&
+			readstate.NeedsAck(channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
+
+// markChannelsAsRead acknowledges the given channels one after another,
+// waiting bulkAckInterval between the acknowledgements. The trees are
+// updated after each acknowledgement. While this is going on, no further
+// channels can be marked as read.
+func (window *Window) markChannelsAsRead(channels []*discordgo.Channel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	window.markingAsReadMutex.Lock()
+	defer window.markingAsReadMutex.Unlock()
+	if window.markingAsRead {
+		return errAlreadyMarkingAsRead
+	}
+	window.markingAsRead = true
+
+	go func() {
+		defer func() {
+			window.markingAsReadMutex.Lock()
+			window.markingAsRead = false
+			window.markingAsReadMutex.Unlock()
+		}()
+
+		var failed int
+		for index, channel := range channels {
+			if index > 0 {
+				time.Sleep(bulkAckInterval)
+			}
+
+			ackError := readstate.Acknowledge(window.session, channel, channel.LastMessageID)
+			if ackError != nil {
+				failed++
+				continue
+			}
+
+			ackedChannel := channel
+			window.app.QueueUpdateDraw(func() {
+				window.updateReadStatusOf(ackedChannel)
+			})
+		}
+
+		if failed > 0 {
+			window.app.QueueUpdateDraw(func() {
+				fmt.Fprintf(window.commandView, "["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]%d of %d channels couldn't be marked as read.\n", failed, len(channels))
+			})
+		}
+	}()
+	return nil
+}
+
+// updateReadStatusOf updates the colours and badges of the given channel and
+// its guild after the channel has been read. Unread markers in chat views
+// showing the channel are removed as well.
+func (window *Window) updateReadStatusOf(channel *discordgo.Channel) {
+	for _, chatView := range window.getChatViewsShowing(channel.ID) {
+		chatView.ClearUnreadMarker()
+	}
+
+	if channel.GuildID == "" {
+		window.privateList.MarkChannelAsRead(channel.ID)
+		return
+	}
+
+	isSelected := window.selectedGuild != nil && window.selectedGuild.ID == channel.GuildID
+	if isSelected {
+		window.channelTree.MarkChannelAsRead(channel.ID)
+	}
+	if guildNode := window.guildList.GetGuildNode(channel.GuildID); guildNode != nil {
+		window.updateServerReadStatus(channel.GuildID, guildNode, isSelected)
+	}
+}
+
+// addMention puts the given message into the mentions inbox.
+func (window *Window) addMention(message *discordgo.Message, channel *discordgo.Channel) {
+	var guild *discordgo.Guild
+	if channel.GuildID != "" {
+		guild, _ = window.session.State.Guild(channel.GuildID)
+	}
+
+	if mentions.Add(newMention(message, channel, guild)) {
+		window.persistMentions()
+	}
+}
+
+// persistMentions saves the mentions inbox, so that it survives restarts.
+func (window *Window) persistMentions() {
+	persistError := mentions.Persist()
+	if persistError != nil {
+		log.Printf("["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]Error saving mentions:\n\t[%s]%s\n", tviewutil.ColorToHex(config.GetTheme().ErrorColor), persistError)
+	}
+}
+
+// jumpToMention loads the channel of the given mention and selects the
+// message that contains the mention.
+func (window *Window) jumpToMention(mention *mentions.Mention) {
+	channel, stateError := window.session.State.Channel(mention.ChannelID)
+	if stateError != nil {
+		window.ShowErrorDialog(fmt.Sprintf("The channel %s isn't available anymore.", mention.ChannelName))
+		return
+	}
+
+	if window.selectedChannel != nil && window.selectedChannel.ID == channel.ID {
+		window.selectMessage(mention.MessageID)
+		return
+	}
+
+	//Loading channels happens asynchronously, therefore LoadChannel takes
+	//care of the selection.
+	window.messageToSelect = mention.MessageID
+	navigateError := window.navigateToChannel(channel)
+	if navigateError != nil {
+		window.messageToSelect = ""
+		window.ShowErrorDialog(navigateError.Error())
+	}
+}
+
+// selectMessage selects the given message in the active pane and focuses the
+// pane. If the message isn't part of the loaded messages, the user is told
+// so instead.
+func (window *Window) selectMessage(messageID string) {
+	if window.chatView.SelectMessage(messageID) {
+		window.app.SetFocus(window.chatView.internalTextView)
+		return
+	}
+
+	window.ShowDialog(config.GetTheme().PrimitiveBackgroundColor,
+		"The message isn't part of the loaded messages. It might have been deleted or older messages have to be loaded first.",
+		func(_ string) {}, "Okay")
+}
+
+func (window *Window) registerMouseFocusListeners() {
+	window.guildList.SetMouseHandler(func(event *tcell.EventMouse) bool {
+		if event.Buttons() == tcell.Button1 {
+			window.app.SetFocus(window.guildList)
+			return true
+		}
+
+		return false
+	})
+	window.channelTree.SetMouseHandler(func(event *tcell.EventMouse) bool {
+		if event.Buttons() == tcell.Button1 {
+			window.app.SetFocus(window.channelTree)
+
+			return true
+		}
+
+		return false
+	})
+
+	window.userList.internalTreeView.SetMouseHandler(func(event *tcell.EventMouse) bool {
 		if event.Buttons() == tcell.Button1 {
 			window.app.SetFocus(window.userList.internalTreeView)
 
@@ -1472,6 +2205,26 @@ func (window *Window) QueueUpdateDrawSynchronized(runnable func()) {
 	close(blocker)
 }
 
+// startTimeRefreshRoutine periodically updates the relative times of the
+// messages in the chatview. If relative times are disabled, nothing happens.
+func (window *Window) startTimeRefreshRoutine() {
+	if !config.GetConfig().RelativeTimes {
+		return
+	}
+
+	go func() {
+		for range time.NewTicker(30 * time.Second).C {
+			for _, chatView := range window.getChatViews() {
+				chatView.Lock()
+				window.QueueUpdateDrawSynchronized(func() {
+					chatView.RefreshTimes()
+				})
+				chatView.Unlock()
+			}
+		}
+	}()
+}
+
 // startMessageHandlerRoutines registers the handlers for certain message
 // events. It updates the cache and the UI if necessary.
 func (window *Window) startMessageHandlerRoutines(input, edit, delete chan *discordgo.Message, bulkDelete chan *discordgo.MessageDeleteBulk) {
@@ -1485,27 +2238,38 @@ func (window *Window) startMessageHandlerRoutines(input, edit, delete chan *disc
 				continue
 			}
 
-			window.chatView.Lock()
-			if window.selectedChannel != nil && message.ChannelID == window.selectedChannel.ID {
-				if message.Author.ID != window.session.State.User.ID {
-					readstate.UpdateReadBuffered(window.session, channel, message.ID)
-				}
+			chatViews := window.getChatViewsShowing(message.ChannelID)
+			if len(chatViews) > 0 && message.Author.ID != window.session.State.User.ID {
+				readstate.UpdateReadBuffered(window.session, channel, message.ID)
+			}
 
+			//Sending the message ends typing.
+			if window.typingUsers.remove(message.ChannelID, message.Author.ID) {
+				window.app.QueueUpdateDraw(window.updateTypingStatus)
+			}
+
+			for _, chatView := range chatViews {
+				chatView.Lock()
 				window.QueueUpdateDrawSynchronized(func() {
-					window.chatView.AddMessage(message)
+					chatView.AddMessage(message)
 				})
+				chatView.Unlock()
 			}
-			window.chatView.Unlock()
 
-			if channel.Type == discordgo.ChannelTypeGuildText && (window.selectedGuild == nil ||
-				window.selectedGuild.ID != channel.GuildID) {
-				for _, guildNode := range window.guildList.GetRoot().GetChildren() {
-					if guildNode.GetReference() == channel.GuildID {
-						window.app.QueueUpdateDraw(func() {
-							window.updateServerReadStatus(channel.GuildID, guildNode, false)
-						})
-						break
-					}
+			if len(chatViews) == 0 && message.Author.ID != window.session.State.User.ID {
+				//Private messages always count as mentions, unless muted.
+				isPrivate := channel.Type == discordgo.ChannelTypeDM || channel.Type == discordgo.ChannelTypeGroupDM
+				readstate.AddUnreadMessage(channel.ID, discordutil.MentionsCurrentUser(window.session.State, message) ||
+					(isPrivate && !readstate.IsChannelMuted(channel)))
+			}
+
+			//The badge of the selected guild has to be updated as well.
+			if channel.Type == discordgo.ChannelTypeGuildText {
+				if guildNode := window.guildList.GetGuildNode(channel.GuildID); guildNode != nil {
+					window.app.QueueUpdateDraw(func() {
+						isSelected := window.selectedGuild != nil && window.selectedGuild.ID == channel.GuildID
+						window.updateServerReadStatus(channel.GuildID, guildNode, isSelected)
+					})
 				}
 			}
 
@@ -1531,7 +2295,17 @@ func (window *Window) startMessageHandlerRoutines(input, edit, delete chan *disc
 				continue
 			}
 
-			if window.selectedChannel == nil || message.ChannelID != window.selectedChannel.ID {
+			if discordutil.MentionsCurrentUser(window.session.State, message) {
+				window.addMention(message, channel)
+			}
+
+			if len(chatViews) == 0 {
+				if !readstate.IsChannelMuted(channel) {
+					window.app.QueueUpdateDraw(func() {
+						window.tabBar.MarkChannelAsUnread(channel.ID)
+					})
+				}
+
 				mentionsCurrentUser := discordutil.MentionsCurrentUserExplicitly(window.session.State, message)
 				if !window.userActive && config.GetConfig().DesktopNotifications {
 					if mentionsCurrentUser ||
@@ -1585,6 +2359,10 @@ func (window *Window) startMessageHandlerRoutines(input, edit, delete chan *disc
 						window.app.QueueUpdateDraw(func() {
 							window.channelTree.MarkChannelAsUnread(channel.ID)
 						})
+					} else {
+						window.app.QueueUpdateDraw(func() {
+							window.channelTree.UpdateCounts(channel.ID)
+						})
 					}
 				}
 			}
@@ -1595,13 +2373,13 @@ func (window *Window) startMessageHandlerRoutines(input, edit, delete chan *disc
 		for messageDeleted := range delete {
 			tempMessageDeleted := messageDeleted
 			window.session.State.MessageRemove(tempMessageDeleted)
-			window.chatView.Lock()
-			if window.selectedChannel != nil && window.selectedChannel.ID == tempMessageDeleted.ChannelID {
+			for _, chatView := range window.getChatViewsShowing(tempMessageDeleted.ChannelID) {
+				chatView.Lock()
 				window.QueueUpdateDrawSynchronized(func() {
-					window.chatView.DeleteMessage(tempMessageDeleted)
+					chatView.DeleteMessage(tempMessageDeleted)
 				})
+				chatView.Unlock()
 			}
-			window.chatView.Unlock()
 		}
 	}()
 
@@ -1615,38 +2393,52 @@ func (window *Window) startMessageHandlerRoutines(input, edit, delete chan *disc
 				}
 			}
 
-			window.chatView.Lock()
-			if window.selectedChannel != nil && window.selectedChannel.ID == tempMessagesDeleted.ChannelID {
+			for _, chatView := range window.getChatViewsShowing(tempMessagesDeleted.ChannelID) {
+				chatView.Lock()
 				window.QueueUpdateDrawSynchronized(func() {
-					window.chatView.DeleteMessages(tempMessagesDeleted.Messages)
+					chatView.DeleteMessages(tempMessagesDeleted.Messages)
 				})
+				chatView.Unlock()
 			}
-			window.chatView.Unlock()
 		}
 	}()
 
 	go func() {
 		for messageEdited := range edit {
 			tempMessageEdited := messageEdited
+			//The state updates messages in place, therefore a copy of the
+			//previous version is required.
+			previousMessage, stateError := window.session.State.Message(tempMessageEdited.ChannelID, tempMessageEdited.ID)
+			if stateError == nil {
+				window.editHistory.recordEdit(&discordgo.Message{
+					ID:              previousMessage.ID,
+					Content:         previousMessage.Content,
+					Timestamp:       previousMessage.Timestamp,
+					EditedTimestamp: previousMessage.EditedTimestamp,
+				}, tempMessageEdited)
+			}
 			window.session.State.MessageAdd(tempMessageEdited)
-			window.chatView.Lock()
-			if window.selectedChannel != nil && window.selectedChannel.ID == tempMessageEdited.ChannelID {
-				for _, message := range window.chatView.data {
+			for _, chatView := range window.getChatViewsShowing(tempMessageEdited.ChannelID) {
+				chatView.Lock()
+				for _, message := range chatView.data {
 					if message.ID == tempMessageEdited.ID {
 						//FIXME Workaround for the fact that discordgo doesn't update already filled fields.
 						message.Content = tempMessageEdited.Content
 						message.Mentions = tempMessageEdited.Mentions
 						message.MentionRoles = tempMessageEdited.MentionRoles
 						message.MentionEveryone = tempMessageEdited.MentionEveryone
+						if tempMessageEdited.EditedTimestamp != "" {
+							message.EditedTimestamp = tempMessageEdited.EditedTimestamp
+						}
 
 						window.QueueUpdateDrawSynchronized(func() {
-							window.chatView.UpdateMessage(message)
+							chatView.UpdateMessage(message)
 						})
 						break
 					}
 				}
+				chatView.Unlock()
 			}
-			window.chatView.Unlock()
 		}
 	}()
 }
@@ -1705,9 +2497,16 @@ func (window *Window) registerGuildHandlers() {
 				window.app.QueueUpdateDraw(func() {
 					if window.selectedChannel != nil && window.selectedChannel.GuildID == guildID {
 						window.chatView.ClearViewAndCache()
+						window.paneMutex.Lock()
 						window.selectedChannel = nil
+						window.paneMutex.Unlock()
 						window.selectedChannelNode = nil
 					}
+					if pane := window.inactivePane; pane != nil && pane.channel != nil && pane.channel.GuildID == guildID {
+						window.paneMutex.Lock()
+						pane.unload()
+						window.paneMutex.Unlock()
+					}
 
 					window.channelTree.Clear()
 					window.userList.Clear()
@@ -1720,6 +2519,35 @@ func (window *Window) registerGuildHandlers() {
 	}()
 }
 
+// registerGuildFolderHandler loads the guild folders and keeps them up to
+// date. Bots can't have guild folders.
+func (window *Window) registerGuildFolderHandler() {
+	if window.session.State.User.Bot {
+		return
+	}
+
+	go func() {
+		folders, loadError := discordutil.LoadGuildFolders(window.session)
+		if loadError != nil {
+			log.Printf("Error loading guild folders (%s).\n", loadError.Error())
+			return
+		}
+
+		window.app.QueueUpdateDraw(func() {
+			window.guildList.SetFolders(folders)
+		})
+	}()
+
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.UserSettingsUpdate) {
+		folders, changed := discordutil.ParseGuildFolders(event)
+		if changed {
+			window.app.QueueUpdateDraw(func() {
+				window.guildList.SetFolders(folders)
+			})
+		}
+	})
+}
+
 func (window *Window) registerGuildMemberHandlers() {
 	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.GuildMembersChunk) {
 		if window.selectedGuild != nil && window.selectedGuild.ID == event.GuildID {
@@ -1751,6 +2579,44 @@ func (window *Window) registerGuildMemberHandlers() {
 				window.userList.AddOrUpdateMember(event.Member)
 			})
 		}
+
+		//Nicknames and role colours are part of the formatted messages.
+		if chatViews := window.getChatViewsOfGuild(event.GuildID); len(chatViews) > 0 {
+			window.app.QueueUpdateDraw(func() {
+				for _, chatView := range chatViews {
+					chatView.RefreshAuthor(event.User.ID)
+				}
+			})
+		}
+	})
+
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.GuildRoleCreate) {
+		window.refreshGuildRoles(event.GuildID)
+	})
+
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.GuildRoleUpdate) {
+		window.refreshGuildRoles(event.GuildID)
+	})
+
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.GuildRoleDelete) {
+		window.refreshGuildRoles(event.GuildID)
+	})
+}
+
+// refreshGuildRoles reloads the user list and formats all messages of the
+// given guild again, since roles decide about grouping and colours.
+func (window *Window) refreshGuildRoles(guildID string) {
+	window.app.QueueUpdateDraw(func() {
+		if window.selectedGuild != nil && window.selectedGuild.ID == guildID {
+			userLoadError := window.userList.LoadGuild(guildID)
+			if userLoadError != nil {
+				window.ShowErrorDialog(userLoadError.Error())
+			}
+		}
+
+		for _, chatView := range window.getChatViewsOfGuild(guildID) {
+			chatView.RefreshAllMessages()
+		}
 	})
 }
 
@@ -1802,6 +2668,120 @@ func (window *Window) registerPrivateChatsHandler() {
 	})
 }
 
+// registerPresenceHandler keeps the presences shown in the user list and
+// the private chat list up to date.
+func (window *Window) registerPresenceHandler() {
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.PresenceUpdate) {
+		if event.User == nil {
+			return
+		}
+
+		if event.GuildID == "" {
+			updateFriendPresence(window.session.State, &event.Presence)
+		}
+
+		window.app.QueueUpdateDraw(func() {
+			window.userList.UpdatePresence(event.GuildID, event.User.ID)
+			window.privateList.UpdatePresence(event.User.ID)
+		})
+	})
+}
+
+// registerUserGuildSettingsHandler applies guilds and channels being muted
+// or unmuted from other clients.
+func (window *Window) registerUserGuildSettingsHandler() {
+	window.scheduleMuteRefreshes(readstate.GetMuteEndTimes())
+
+	//The raw event is required, since the end times of mutes are missing in
+	//the parsed settings.
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.Event) {
+		switch event.Type {
+		case "READY":
+			//The end times have been taken over when connecting.
+			window.scheduleMuteRefreshes(readstate.GetMuteEndTimes())
+			window.app.QueueUpdateDraw(window.refreshMuteStates)
+		case "USER_GUILD_SETTINGS_UPDATE":
+			settingsUpdate, isSettingsUpdate := event.Struct.(*discordgo.UserGuildSettingsUpdate)
+			if !isSettingsUpdate || settingsUpdate.UserGuildSettings == nil {
+				return
+			}
+
+			readstate.UpdateGuildSettings(settingsUpdate.UserGuildSettings)
+			muteEndTimes, parseError := discordutil.GetMuteEndTimes(event.RawData)
+			if parseError != nil {
+				log.Printf("["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]Error reading mute end times:\n\t[%s]%s\n", tviewutil.ColorToHex(config.GetTheme().ErrorColor), parseError)
+			} else {
+				readstate.UpdateMuteEndTimes(muteEndTimes)
+				window.scheduleMuteRefreshes(muteEndTimes)
+			}
+			window.app.QueueUpdateDraw(window.refreshMuteStates)
+		}
+	})
+}
+
+// scheduleMuteRefreshes refreshes the mute states once the given mutes
+// expire. Zero times and times in the past are ignored.
+func (window *Window) scheduleMuteRefreshes(endTimes map[string]time.Time) {
+	now := time.Now()
+	for _, endTime := range endTimes {
+		if endTime.After(now) {
+			time.AfterFunc(endTime.Sub(now), func() {
+				window.app.QueueUpdateDraw(window.refreshMuteStates)
+			})
+		}
+	}
+}
+
+// registerTypingHandler keeps track of who is typing. Each user is shown
+// as typing until typingDuration has passed since their last notification.
+func (window *Window) registerTypingHandler() {
+	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.TypingStart) {
+		if event.UserID == window.session.State.User.ID {
+			return
+		}
+
+		window.typingUsers.add(event.ChannelID, event.UserID, time.Now())
+		window.app.QueueUpdateDraw(window.updateTypingStatus)
+		time.AfterFunc(typingDuration, func() {
+			window.app.QueueUpdateDraw(window.updateTypingStatus)
+		})
+	})
+}
+
+// updateTypingStatus shows who is typing in the channel of the active pane.
+// The status line is hidden while nobody is typing.
+func (window *Window) updateTypingStatus() {
+	var names []string
+	if window.selectedChannel != nil {
+		for _, userID := range window.typingUsers.get(window.selectedChannel.ID, time.Now()) {
+			names = append(names, window.getTypingUserName(window.selectedChannel, userID))
+		}
+	}
+
+	typingText := formatTypingText(names)
+	window.typingStatus.SetText(typingText)
+	window.typingStatus.SetVisible(typingText != "")
+}
+
+// getTypingUserName returns the name of the given user as it should be shown
+// in the given channel. Users that aren't cached are shown as "Someone".
+func (window *Window) getTypingUserName(channel *discordgo.Channel, userID string) string {
+	if channel.GuildID != "" {
+		member, cacheError := window.session.State.Member(channel.GuildID, userID)
+		if cacheError == nil {
+			return discordutil.GetMemberName(member)
+		}
+	}
+
+	for _, recipient := range channel.Recipients {
+		if recipient.ID == userID {
+			return discordutil.GetUserName(recipient)
+		}
+	}
+
+	return "Someone"
+}
+
 func (window *Window) isChannelEventRelevant(channelEvent *discordgo.Channel) bool {
 	if window.selectedGuild == nil {
 		return false
@@ -1840,6 +2820,11 @@ func (window *Window) registerGuildChannelHandler() {
 	})
 
 	window.session.AddHandler(func(s *discordgo.Session, event *discordgo.ChannelDelete) {
+		window.app.QueueUpdateDraw(func() {
+			window.tabBar.CloseChannel(event.ID)
+			window.persistTabs()
+		})
+
 		if window.isChannelEventRelevant(event.Channel) {
 			if window.previousChannelNode != nil && window.previousChannelNode.GetReference() == event.ID {
 				window.previousGuildNode = nil
@@ -1849,13 +2834,19 @@ func (window *Window) registerGuildChannelHandler() {
 			}
 
 			if window.selectedChannelNode != nil && window.selectedChannelNode.GetReference() == event.ID {
+				window.paneMutex.Lock()
 				window.selectedChannel = nil
+				window.paneMutex.Unlock()
 				window.selectedChannelNode = nil
 				window.app.QueueUpdateDraw(func() {
 					window.chatView.ClearViewAndCache()
 				})
 			}
 
+			if pane := window.inactivePane; pane != nil && pane.channel != nil && pane.channel.ID == event.ID {
+				window.app.QueueUpdateDraw(pane.unload)
+			}
+
 			//On purpose, since we don't care much about removing the channel timely.
 			window.app.QueueUpdateDraw(func() {
 				window.channelTree.Lock()
@@ -2032,6 +3023,38 @@ func (window *Window) handleGlobalShortcuts(event *tcell.EventKey) *tcell.EventK
 		if err != nil {
 			window.ShowErrorDialog(err.Error())
 		}
+	} else if shortcuts.ToggleSplitView.Equals(event) {
+		window.toggleSplitView()
+	} else if shortcuts.SwitchChatPane.Equals(event) {
+		window.switchActivePane()
+	} else if shortcuts.ToggleSplitDirection.Equals(event) {
+		window.toggleSplitDirection()
+	} else if shortcuts.ShowMentionsInbox.Equals(event) {
+		window.showMentionsInbox()
+	} else if shortcuts.ShowQuickSwitcher.Equals(event) {
+		window.showQuickSwitcher()
+	} else if shortcuts.JumpToNextUnread.Equals(event) {
+		window.jumpToNextUnread(false)
+	} else if shortcuts.JumpToNextMention.Equals(event) {
+		window.jumpToNextUnread(true)
+	} else if shortcuts.OpenNewTab.Equals(event) {
+		window.openNewTab()
+	} else if shortcuts.CloseTab.Equals(event) {
+		window.closeActiveTab()
+	} else if shortcuts.SwitchToNextTab.Equals(event) {
+		if window.tabBar.GetTabCount() > 1 {
+			window.switchToTab(window.tabBar.GetRelativeTab(1))
+		}
+	} else if shortcuts.SwitchToPreviousTab.Equals(event) {
+		if window.tabBar.GetTabCount() > 1 {
+			window.switchToTab(window.tabBar.GetRelativeTab(-1))
+		}
+	} else if shortcuts.MoveTabRight.Equals(event) {
+		window.tabBar.MoveActiveTab(1)
+		window.persistTabs()
+	} else if shortcuts.MoveTabLeft.Equals(event) {
+		window.tabBar.MoveActiveTab(-1)
+		window.persistTabs()
 	} else if shortcuts.FocusGuildContainer.Equals(event) {
 		window.SwitchToGuildsPage()
 		window.app.SetFocus(window.guildList)
@@ -2192,6 +3215,7 @@ func (window *Window) SwitchToPreviousChannel() error {
 		if window.leftArea.GetCurrentPage() != guildPageName {
 			window.leftArea.SwitchToPage(guildPageName)
 		}
+		window.guildList.ExpandFolderOf(window.previousGuild.ID)
 		window.guildList.SetCurrentNode(window.previousGuildNode)
 		window.guildList.onGuildSelect(window.previousGuildNode, window.previousGuild.ID)
 		window.channelTree.SetCurrentNode(window.previousChannelNode)
@@ -2250,9 +3274,27 @@ func (window *Window) LoadChannel(channel *discordgo.Channel) error {
 
 	discordutil.SortMessagesByTimestamp(messages)
 
+	//The tabs only belong to the primary pane of the split view.
+	var previousTab, tab *channelTab
+	if window.primaryPaneActive {
+		previousTab = window.tabBar.GetActiveTab()
+		if previousTab != nil && previousTab.channel != nil && previousTab.channel.ID != channel.ID {
+			previousTab.draft = window.messageInput.GetText()
+			previousTab.chatViewState = window.chatView.GetState()
+		}
+		tab = window.tabBar.ShowChannel(channel)
+	}
+
+	//Needs to happen before the channel is being acknowledged, since we'd
+	//lose the information about where the user stopped reading otherwise.
+	window.chatView.SetUnreadMarker(readstate.GetLastReadMessageID(channel.ID))
 	window.chatView.SetMessages(messages)
 	window.chatView.ClearSelection()
 	window.chatView.internalTextView.ScrollToEnd()
+	if tab != nil && tab.chatViewState != nil {
+		window.chatView.RestoreState(tab.chatViewState)
+		tab.chatViewState = nil
+	}
 
 	window.UpdateChatHeader(channel)
 
@@ -2276,7 +3318,9 @@ func (window *Window) LoadChannel(channel *discordgo.Channel) error {
 		window.selectedChannelNode.SetColor(tview.Styles.PrimaryTextColor)
 	}
 
+	window.paneMutex.Lock()
 	window.selectedChannel = channel
+	window.paneMutex.Unlock()
 	//FIXME this is a bit bad, since it could be wrong
 	window.selectedChannelNode = window.channelTree.GetCurrentNode()
 
@@ -2297,12 +3341,37 @@ func (window *Window) LoadChannel(channel *discordgo.Channel) error {
 
 	window.exitMessageEditModeAndKeepText()
 
+	//The text is kept when replacing the channel of the current tab.
+	if tab != previousTab {
+		window.messageInput.SetText(tab.draft)
+		tab.draft = ""
+	}
+	if tab != nil {
+		window.persistTabs()
+	}
+
 	if config.GetConfig().FocusMessageInputAfterChannelSelection {
 		window.app.SetFocus(window.messageInput.internalTextView)
 	}
 
+	if window.messageToSelect != "" {
+		window.selectMessage(window.messageToSelect)
+		window.messageToSelect = ""
+	}
+
+	window.updateTypingStatus()
+
 	go func() {
 		readstate.UpdateRead(window.session, channel, channel.LastMessageID)
+		window.app.QueueUpdateDraw(func() {
+			if channel.GuildID == "" {
+				window.privateList.MarkChannelAsRead(channel.ID)
+			} else {
+				window.channelTree.UpdateCounts(channel.ID)
+				window.guildList.UpdateGuildBadge(channel.GuildID)
+				window.guildList.UpdateFolderReadStatus(channel.GuildID)
+			}
+		})
 
 		// Here we make the assumption that the channel we are loading must be part
 		// of the currently loaded guild, since we don't allow loading a channel of
@@ -2312,13 +3381,11 @@ func (window *Window) LoadChannel(channel *discordgo.Channel) error {
 			if cacheError == nil {
 				window.selectedGuild = guild
 				window.app.QueueUpdateDraw(func() {
-					for _, guildNode := range window.guildList.GetRoot().GetChildren() {
-						if guildNode.GetReference() == channel.GuildID {
-							window.guildList.SetCurrentNode(guildNode)
-							guildNode.SetColor(tview.Styles.ContrastBackgroundColor)
-							window.selectedGuildNode = guildNode
-							break
-						}
+					if guildNode := window.guildList.GetGuildNode(channel.GuildID); guildNode != nil {
+						window.guildList.ExpandFolderOf(channel.GuildID)
+						window.guildList.SetCurrentNode(guildNode)
+						guildNode.SetColor(tview.Styles.ContrastBackgroundColor)
+						window.selectedGuildNode = guildNode
 					}
 				})
 			}
@@ -2328,6 +3395,435 @@ func (window *Window) LoadChannel(channel *discordgo.Channel) error {
 	return nil
 }
 
+// restoreTabs opens the tabs of the previous session and loads the channel
+// of the tab that was active. Channels that can't be read anymore are
+// skipped.
+func (window *Window) restoreTabs() {
+	conf := config.GetConfig()
+	var activeChannel *discordgo.Channel
+	for index, channelID := range conf.OpenTabs {
+		channel, stateError := window.session.State.Channel(channelID)
+		if stateError != nil {
+			continue
+		}
+
+		if channel.GuildID != "" && !discordutil.HasReadMessagesPermission(channel.ID, window.session.State) {
+			continue
+		}
+
+		window.tabBar.OpenTab(channel, !readstate.HasBeenRead(channel, channel.LastMessageID))
+		if index == conf.ActiveTab || activeChannel == nil {
+			activeChannel = channel
+		}
+	}
+
+	if activeChannel != nil {
+		navigateError := window.navigateToChannel(activeChannel)
+		if navigateError != nil {
+			window.ShowErrorDialog(navigateError.Error())
+		}
+	}
+}
+
+// persistTabs saves the open tabs, so that they can be restored on the next
+// start. Nothing is written if the tabs haven't changed.
+func (window *Window) persistTabs() {
+	conf := config.GetConfig()
+	channelIDs, activeIndex := window.tabBar.GetChannelIDs()
+	if activeIndex == conf.ActiveTab && len(channelIDs) == len(conf.OpenTabs) {
+		unchanged := true
+		for index, channelID := range channelIDs {
+			if conf.OpenTabs[index] != channelID {
+				unchanged = false
+				break
+			}
+		}
+
+		if unchanged {
+			return
+		}
+	}
+
+	conf.OpenTabs = channelIDs
+	conf.ActiveTab = activeIndex
+	persistError := config.PersistConfig()
+	if persistError != nil {
+		log.Printf("["+tviewutil.ColorToHex(config.GetTheme().ErrorColor)+"]Error saving open tabs:\n\t[%s]%s\n", tviewutil.ColorToHex(config.GetTheme().ErrorColor), persistError)
+	}
+}
+
+// switchToTab loads the channel of the given tab, selecting it in the guild
+// or private chat list.
+func (window *Window) switchToTab(tab *channelTab) {
+	window.activatePrimaryPane()
+	if tab == window.tabBar.GetActiveTab() || tab.channel == nil {
+		return
+	}
+
+	navigateError := window.navigateToChannel(tab.channel)
+	if navigateError != nil {
+		window.ShowErrorDialog(navigateError.Error())
+	}
+}
+
+// openNewTab opens an empty tab, which the next selected channel will be
+// loaded into.
+func (window *Window) openNewTab() {
+	window.activatePrimaryPane()
+	activeTab := window.tabBar.GetActiveTab()
+	if activeTab == nil || activeTab.channel == nil {
+		return
+	}
+
+	activeTab.draft = window.messageInput.GetText()
+	activeTab.chatViewState = window.chatView.GetState()
+	window.tabBar.OpenEmptyTab()
+	window.unloadChannel()
+}
+
+// closeActiveTab closes the current tab and loads the tab next to it. If
+// there's no tab left, the chatview is cleared.
+func (window *Window) closeActiveTab() {
+	window.activatePrimaryPane()
+	nextTab := window.tabBar.CloseActiveTab()
+	if nextTab == nil {
+		window.unloadChannel()
+	} else if navigateError := window.navigateToChannel(nextTab.channel); navigateError != nil {
+		window.unloadChannel()
+		window.ShowErrorDialog(navigateError.Error())
+	}
+	window.persistTabs()
+}
+
+// unloadChannel clears the chatview and the message input, leaving no
+// channel selected.
+func (window *Window) unloadChannel() {
+	if window.selectedChannelNode != nil {
+		window.selectedChannelNode.SetColor(tview.Styles.PrimaryTextColor)
+	}
+
+	window.paneMutex.Lock()
+	window.selectedChannel = nil
+	window.paneMutex.Unlock()
+	window.selectedChannelNode = nil
+	window.chatView.ClearViewAndCache()
+	window.chatView.SetTitle("")
+	window.exitMessageEditMode()
+	window.updateTypingStatus()
+}
+
+// navigateToChannel selects the given channel in the guild or private chat
+// list, loading it the same way as if the user had selected it manually.
+func (window *Window) navigateToChannel(channel *discordgo.Channel) error {
+	switch channel.Type {
+	case discordgo.ChannelTypeDM, discordgo.ChannelTypeGroupDM:
+		channelNode := tviewutil.FindNodeByReference(window.privateList.GetComponent().GetRoot(), channel.ID)
+		if channelNode == nil {
+			return fmt.Errorf("Chat %s not found", discordutil.GetPrivateChannelName(channel))
+		}
+
+		window.SwitchToFriendsPage()
+		window.privateList.GetComponent().SetCurrentNode(channelNode)
+		window.privateList.onChannelSelect(channelNode, channel.ID)
+	case discordgo.ChannelTypeGuildText:
+		if !discordutil.HasReadMessagesPermission(channel.ID, window.session.State) {
+			return fmt.Errorf("No read permissions for channel: %s", channel.Name)
+		}
+
+		window.SwitchToGuildsPage()
+		if window.selectedGuild == nil || window.selectedGuild.ID != channel.GuildID {
+			guildNode := window.guildList.GetGuildNode(channel.GuildID)
+			if guildNode == nil {
+				return fmt.Errorf("Unable to load guild of channel: %s", channel.Name)
+			}
+
+			window.guildList.ExpandFolderOf(channel.GuildID)
+			window.guildList.SetCurrentNode(guildNode)
+			window.guildList.onGuildSelect(guildNode, channel.GuildID)
+		}
+
+		channelNode := tviewutil.FindNodeByReference(window.channelTree.GetRoot(), channel.ID)
+		if channelNode == nil {
+			return fmt.Errorf("Channel %s not found", channel.Name)
+		}
+
+		window.channelTree.SetCurrentNode(channelNode)
+		window.channelTree.onChannelSelect(channel.ID)
+	default:
+		return fmt.Errorf("Invalid channel type: %v", channel.Type)
+	}
+
+	return nil
+}
+
+// navigateToGuild selects the given guild in the guild list and focuses the
+// channel tree, so that a channel can be picked.
+func (window *Window) navigateToGuild(guildID string) error {
+	guildNode := window.guildList.GetGuildNode(guildID)
+	if guildNode == nil {
+		return fmt.Errorf("Guild %s not found", guildID)
+	}
+
+	window.SwitchToGuildsPage()
+	window.guildList.ExpandFolderOf(guildID)
+	window.guildList.SetCurrentNode(guildNode)
+	if window.selectedGuild == nil || window.selectedGuild.ID != guildID {
+		window.guildList.onGuildSelect(guildNode, guildID)
+	}
+	window.app.SetFocus(window.channelTree)
+
+	return nil
+}
+
+// chatPane is a ChatView of the split view and the channel shown in it.
+type chatPane struct {
+	chatView    *ChatView
+	channel     *discordgo.Channel
+	channelNode *tview.TreeNode
+}
+
+// unload clears the pane, leaving no channel shown in it.
+func (pane *chatPane) unload() {
+	pane.chatView.ClearViewAndCache()
+	pane.chatView.SetTitle("")
+	pane.channel = nil
+	pane.channelNode = nil
+}
+
+// getPanes returns a copy of all panes, starting with the active one. Since
+// the panes are swapped on the UI goroutine, this is safe to call from any
+// goroutine. The channelNode of the copies isn't set.
+func (window *Window) getPanes() []chatPane {
+	window.paneMutex.RLock()
+	defer window.paneMutex.RUnlock()
+
+	panes := []chatPane{{chatView: window.chatView, channel: window.selectedChannel}}
+	if window.inactivePane != nil {
+		panes = append(panes, chatPane{chatView: window.inactivePane.chatView, channel: window.inactivePane.channel})
+	}
+
+	return panes
+}
+
+// getChatViews returns the ChatViews of all panes, starting with the active
+// one.
+func (window *Window) getChatViews() []*ChatView {
+	var chatViews []*ChatView
+	for _, pane := range window.getPanes() {
+		chatViews = append(chatViews, pane.chatView)
+	}
+
+	return chatViews
+}
+
+// getChatViewsShowing returns the ChatViews of all panes that show the given
+// channel.
+func (window *Window) getChatViewsShowing(channelID string) []*ChatView {
+	var chatViews []*ChatView
+	for _, pane := range window.getPanes() {
+		if pane.channel != nil && pane.channel.ID == channelID {
+			chatViews = append(chatViews, pane.chatView)
+		}
+	}
+
+	return chatViews
+}
+
+// getChatViewsOfGuild returns the ChatViews of all panes that show a
+// channel of the given guild.
+func (window *Window) getChatViewsOfGuild(guildID string) []*ChatView {
+	var chatViews []*ChatView
+	for _, pane := range window.getPanes() {
+		if pane.channel != nil && pane.channel.GuildID == guildID {
+			chatViews = append(chatViews, pane.chatView)
+		}
+	}
+
+	return chatViews
+}
+
+// getChannelOf returns the channel shown by the pane of the given ChatView.
+func (window *Window) getChannelOf(chatView *ChatView) *discordgo.Channel {
+	for _, pane := range window.getPanes() {
+		if pane.chatView == chatView {
+			return pane.channel
+		}
+	}
+
+	return nil
+}
+
+// toggleSplitView adds a second pane to the chat area and activates it, so
+// that the next channel that is selected is loaded into it. If the view is
+// already split, the second pane is removed instead.
+func (window *Window) toggleSplitView() {
+	if window.inactivePane == nil {
+		chatView := window.chatView.newSibling()
+		window.initChatView(chatView)
+		window.chatPanes.AddItem(chatView.GetPrimitive(), 0, 1, false)
+		window.paneMutex.Lock()
+		window.inactivePane = &chatPane{chatView: chatView}
+		window.paneMutex.Unlock()
+		window.switchActivePane()
+		return
+	}
+
+	window.activatePrimaryPane()
+	removedView := window.inactivePane.chatView.GetPrimitive()
+	window.chatPanes.RemoveItem(removedView)
+	window.paneMutex.Lock()
+	window.inactivePane = nil
+	window.paneMutex.Unlock()
+	if window.app.GetFocus() == removedView {
+		window.app.SetFocus(window.chatView.GetPrimitive())
+	}
+	window.updatePaneBorders()
+}
+
+// toggleSplitDirection switches between showing the panes side by side and
+// showing them on top of each other.
+func (window *Window) toggleSplitDirection() {
+	conf := config.GetConfig()
+	conf.SplitViewSideBySide = !conf.SplitViewSideBySide
+	if conf.SplitViewSideBySide {
+		window.chatPanes.SetDirection(tview.FlexColumn)
+	} else {
+		window.chatPanes.SetDirection(tview.FlexRow)
+	}
+
+	persistError := config.PersistConfig()
+	if persistError != nil {
+		window.ShowErrorDialog(fmt.Sprintf("Error saving the split direction: %s", persistError.Error()))
+	}
+}
+
+// switchActivePane makes the inactive pane of the split view the active one.
+// Focus is moved to it if the previously active pane had focus. Since the
+// message that is being edited belongs to the previously active pane, the
+// edit mode is left.
+func (window *Window) switchActivePane() {
+	if window.inactivePane == nil {
+		return
+	}
+
+	previousPane := &chatPane{
+		chatView:    window.chatView,
+		channel:     window.selectedChannel,
+		channelNode: window.selectedChannelNode,
+	}
+	window.paneMutex.Lock()
+	window.chatView = window.inactivePane.chatView
+	window.selectedChannel = window.inactivePane.channel
+	window.selectedChannelNode = window.inactivePane.channelNode
+	window.inactivePane = previousPane
+	window.paneMutex.Unlock()
+	window.primaryPaneActive = !window.primaryPaneActive
+
+	if window.editingMessageID != nil {
+		window.exitMessageEditMode()
+	}
+
+	if window.app.GetFocus() == previousPane.chatView.GetPrimitive() {
+		window.app.SetFocus(window.chatView.GetPrimitive())
+	}
+
+	window.updatePaneBorders()
+	window.updateTypingStatus()
+}
+
+// activatePrimaryPane makes sure that the pane the tabs belong to is active.
+func (window *Window) activatePrimaryPane() {
+	if !window.primaryPaneActive {
+		window.switchActivePane()
+	}
+}
+
+// updatePaneBorders highlights the border of the active pane, as long as the
+// view is split.
+func (window *Window) updatePaneBorders() {
+	if window.inactivePane == nil {
+		window.chatView.internalTextView.SetBorderColor(tview.Styles.BorderColor)
+		return
+	}
+
+	window.chatView.internalTextView.SetBorderColor(tview.Styles.ContrastBackgroundColor)
+	window.inactivePane.chatView.internalTextView.SetBorderColor(tview.Styles.BorderColor)
+}
+
+// loadOlderMessages requests the page of messages that precedes the oldest
+// message in the ChatView, adds it to the state cache and prepends it to the
+// ChatView. If the page isn't full, the start of the channel has been reached
+// and the ChatView won't request any further pages.
+func (window *Window) loadOlderMessages(chatView *ChatView) {
+	channel := window.getChannelOf(chatView)
+	if channel == nil || chatView.oldestMessageID == "" {
+		return
+	}
+
+	chatView.loadingOlderMessages = true
+	//Messages of blocked users might not be displayed, therefore the oldest
+	//message that has been fetched is used instead of the oldest one shown.
+	beforeID := chatView.oldestMessageID
+	go func() {
+		messages, discordError := window.session.ChannelMessages(channel.ID, 100, beforeID, "", "")
+		if discordError != nil {
+			window.app.QueueUpdateDraw(func() {
+				if chatView.oldestMessageID == beforeID {
+					chatView.loadingOlderMessages = false
+				}
+				window.ShowErrorDialog(fmt.Sprintf("Error loading older messages: %s", discordError.Error()))
+			})
+			return
+		}
+
+		if channel.GuildID != "" {
+			for _, message := range messages {
+				message.GuildID = channel.GuildID
+			}
+		}
+		discordutil.SortMessagesByTimestamp(messages)
+		window.prependMessagesToCache(channel, messages)
+
+		chatView.Lock()
+		defer chatView.Unlock()
+		window.QueueUpdateDrawSynchronized(func() {
+			//The user might have switched channels in the meantime. Even if
+			//the channel has been loaded again, the messages might be
+			//outdated, which is the case if the oldest message changed.
+			shownChannel := window.getChannelOf(chatView)
+			if shownChannel == nil || shownChannel.ID != channel.ID ||
+				chatView.oldestMessageID != beforeID {
+				return
+			}
+
+			chatView.loadingOlderMessages = false
+			chatView.reachedChannelStart = len(messages) < 100
+			chatView.PrependMessages(messages)
+		})
+	}()
+}
+
+// prependMessagesToCache adds older messages to the front of the channels
+// message cache, skipping all messages that are already present.
+func (window *Window) prependMessagesToCache(channel *discordgo.Channel, messages []*discordgo.Message) {
+	window.session.State.Lock()
+	defer window.session.State.Unlock()
+
+	cachedMessages := make(map[string]struct{}, len(channel.Messages))
+	for _, message := range channel.Messages {
+		cachedMessages[message.ID] = struct{}{}
+	}
+
+	olderMessages := make([]*discordgo.Message, 0, len(messages))
+	for _, message := range messages {
+		if _, contains := cachedMessages[message.ID]; !contains {
+			olderMessages = append(olderMessages, message)
+		}
+	}
+
+	channel.Messages = append(olderMessages, channel.Messages...)
+}
+
 // UpdateChatHeader updates the bordertitle of the chatviews container.o
 // The title consist of the channel name and its topic for guild channels.
 // For private channels it's either the recipient in a dm, or all recipients